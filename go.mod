@@ -2,8 +2,9 @@ module tubes2_be_bfc
 
 go 1.24.2
 
+require github.com/PuerkitoBio/goquery v1.10.3
+
 require (
-	github.com/PuerkitoBio/goquery v1.10.3 // indirect
 	github.com/andybalholm/cascadia v1.3.3 // indirect
 	golang.org/x/net v0.39.0 // indirect
-)
\ No newline at end of file
+)
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"tubes2_be_bfc/src/cmd"
+)
+
+// setCORSHeaders menambahkan header CORS yang sama seperti /api/data
+func setCORSHeaders(w http.ResponseWriter, methods string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", methods)
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// handleValidate mengembalikan laporan validasi dataset yang sedang dimuat
+func handleValidate(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, currentDataset().Report)
+}
+
+// handleAlgorithms mengembalikan algoritma yang terdaftar beserta parameternya
+func handleAlgorithms(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cmd.Algorithms())
+}
+
+// handleCache mengembalikan statistik cache hasil pencarian
+func handleCache(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	if Cache == nil {
+		writeJSON(w, http.StatusOK, cmd.CacheStats{})
+		return
+	}
+	writeJSON(w, http.StatusOK, Cache.Stats())
+}
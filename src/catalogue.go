@@ -0,0 +1,143 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"tubes2_be_bfc/src/cmd"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+type ElementListResponse struct {
+	Total    int                  `json:"total"`
+	Offset   int                  `json:"offset"`
+	Limit    int                  `json:"limit"`
+	Elements []cmd.ElementSummary `json:"elements"`
+}
+
+type ElementUsageResponse struct {
+	Element string             `json:"element"`
+	UsedIn  []cmd.ElementUsage `json:"usedIn"`
+}
+
+// queryInt membaca parameter query bilangan bulat non-negatif
+func queryInt(r *http.Request, key string, fallback int) (int, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return fallback, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// handleElements: GET /api/elements?tier=&offset=&limit=
+func handleElements(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	offset, ok := queryInt(r, "offset", 0)
+	if !ok {
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidQuery, "offset", "offset must be a non-negative integer"))
+		return
+	}
+	limit, ok := queryInt(r, "limit", defaultPageLimit)
+	if !ok || limit == 0 || limit > maxPageLimit {
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidQuery, "limit", "limit must be between 1 and 1000"))
+		return
+	}
+
+	ds := currentDataset()
+	elements := cmd.ListElements(ds.Recipes, ds.Tiers, ds.TreeCounts)
+	if r.URL.Query().Has("tier") {
+		tier, ok := queryInt(r, "tier", 0)
+		if !ok {
+			writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidQuery, "tier", "tier must be a non-negative integer"))
+			return
+		}
+		filtered := elements[:0]
+		for _, el := range elements {
+			if el.Tier == tier {
+				filtered = append(filtered, el)
+			}
+		}
+		elements = filtered
+	}
+
+	total := len(elements)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	writeJSON(w, http.StatusOK, ElementListResponse{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Elements: elements[offset:end],
+	})
+}
+
+// handleElement: GET /api/elements/{name}
+func handleElement(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	ds := currentDataset()
+	name, ok := ds.Names.Resolve(r.PathValue("name"))
+	if !ok {
+		writeError(w, elementNotFound(ds, "name", r.PathValue("name")))
+		return
+	}
+
+	detail, _ := cmd.GetElement(ds.Recipes, ds.Tiers, ds.TreeCounts, name)
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// handleElementUsage: GET /api/elements/{name}/used-in
+func handleElementUsage(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	ds := currentDataset()
+	name, ok := ds.Names.Resolve(r.PathValue("name"))
+	if !ok {
+		writeError(w, elementNotFound(ds, "name", r.PathValue("name")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ElementUsageResponse{
+		Element: name,
+		UsedIn:  cmd.UsedIn(ds.Index, ds.Tiers, name),
+	})
+}
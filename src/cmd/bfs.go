@@ -26,108 +26,110 @@ func flattenTreeList(trees []*ElementNode) []ElementNode {
 	return result
 }
 
-func isUnbuildable(e string, recipes RecipeMap) bool {
-	return !isBase(e) && len(recipes[e]) == 0
+// MemoCache menyimpan pohon yang sudah dibangun per kunci. Bahan hanya dibangun
+// sebanyak yang berguna bagi produknya, sehingga setiap entri mencatat batas
+// jumlah pohon saat dibangun.
+type MemoCache struct {
+	mu    sync.Mutex
+	store map[string]memoEntry
+}
+
+type memoEntry struct {
+	trees []*ElementNode
+	limit int
 }
 
-func countNodes(node *ElementNode) int {
-	if node == nil {
-		return 0
+func newMemoCache() *MemoCache {
+	return &MemoCache{store: make(map[string]memoEntry)}
+}
+
+// get mengembalikan paling banyak limit pohon untuk key. Entri hanya dipakai jika
+// dibangun dengan batas minimal limit, atau jumlah pohonnya di bawah batasnya
+// sehingga sudah memuat semua pohon.
+func (c *MemoCache) get(key string, limit int) ([]*ElementNode, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.store[key]
+	if !ok || (entry.limit < limit && len(entry.trees) >= entry.limit) {
+		return nil, false
 	}
-	count := 1
-	for _, child := range node.Children {
-		count += countNodes(child)
+	if len(entry.trees) > limit {
+		return entry.trees[:limit:limit], true
 	}
-	return count
+	return entry.trees, true
 }
 
-type MemoCache struct {
-	mu    sync.Mutex
-	store map[string][]*ElementNode
+// put menyimpan pohon untuk key kecuali sudah ada entri dengan batas yang lebih besar
+func (c *MemoCache) put(key string, trees []*ElementNode, limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.store[key]; ok && entry.limit >= limit {
+		return
+	}
+	c.store[key] = memoEntry{trees: trees, limit: limit}
 }
 
 func bfsBuildTree(
 	recipes RecipeMap,
 	tiers TierMap,
 	target string,
+	need uint64,
+	depth int,
 	maxPaths int,
 	cache *MemoCache, 
+	run *searchRun,
 ) []*ElementNode {
 	var result []*ElementNode
+	run.metrics.visits.Add(1)
+
+	// Elemen wajib yang masih harus muncul di bawah target
+	need = run.req.remaining(target, need)
+	key := run.req.cacheKey(target, need)
 
-	if isBase(target) {
+	if run.opts.isLeaf(target) {
+		if need != 0 {
+			return nil
+		}
 		node := &ElementNode{
 			Result:   target,
 			Sources:  nil,
 			Children: nil,
 		}
-		cache.mu.Lock()
-		cache.store[target] = []*ElementNode{node}
-		cache.mu.Unlock()
 		return []*ElementNode{node}
 	}
 
 
-	cache.mu.Lock()
-	if val, ok := cache.store[target]; ok {
-		cache.mu.Unlock()
+	if val, ok := cache.get(key, maxPaths); ok {
+		run.metrics.memoHits.Add(1)
 		return val
 	}
-	cache.mu.Unlock()
+
+	if run.stopped() {
+		return nil
+	}
 
 	combos, exists := recipes[target]
 	if !exists {
 		return nil
 	}
 
+	run.emit(EventExpand, target, depth, nil, 0)
+	run.metrics.expansions.Add(1)
 	parentTier := tiers[target]
-	queue := make(chan []string, len(combos))
-	resultsChan := make(chan *ElementNode, maxPaths)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var wg sync.WaitGroup
-
-	worker := func() {
-		defer wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case pair, ok := <-queue:
-				if !ok {
-					return
-				}
-
-				if isUnbuildable(pair[0], recipes) || isUnbuildable(pair[1], recipes) {
-					continue
-				}
-
-				tierA := tiers[pair[0]]
-				tierB := tiers[pair[1]]
-				if tierA >= parentTier || tierB >= parentTier {
-					continue
-				}
-
-				// Recursively build children using same memo cache
-				leftTrees := bfsBuildTree(recipes, tiers, pair[0], maxPaths, cache)
-				rightTrees := bfsBuildTree(recipes, tiers, pair[1], maxPaths, cache)
-
-				for _, left := range leftTrees {
-					for _, right := range rightTrees {
-						select {
-						case resultsChan <- &ElementNode{
-							Result:   target,
-							Sources:  pair,
-							Children: []*ElementNode{left, right},
-						}:
-						case <-ctx.Done():
-							return
-						}
-					}
-				}
-			}
+
+	expand := func(ctx context.Context, pair []string) []*ElementNode {
+		if run.prune(recipes, tiers, parentTier, pair) {
+			return nil
+		}
+
+		// Recursively build children using same memo cache
+		nodes := expandRequired(ctx, run, target, pair, need, maxPaths, func(element string, need uint64, limit int) []*ElementNode {
+			return bfsBuildTree(recipes, tiers, element, need, depth+1, limit, cache, run)
+		})
+		if len(nodes) > 0 {
+			run.emit(EventRecipe, target, depth, pair, len(nodes))
 		}
+		return nodes
 	}
 
 	// Launch worker goroutines
@@ -135,50 +137,35 @@ func bfsBuildTree(
 	if workerCount > 16 {
 		workerCount = 16
 	}
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go worker()
-	}
+	result = combineParallel(combos, workerCount, maxPaths, run, expand)
+	run.emit(EventComplete, target, depth, nil, len(result))
 
-	go func() {
-		for _, pair := range combos {
-			queue <- pair
-		}
-		close(queue)
-	}()
-
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	for node := range resultsChan {
-		result = append(result, node)
-		if len(result) >= maxPaths {
-			cancel()
-			break
-		}
-	}
-
-	cache.mu.Lock()
-	cache.store[target] = result
-	cache.mu.Unlock()
+	cache.put(key, result, maxPaths)
 
 	return result
 }
 
-func MainBfs(recipes RecipeMap, tiers TierMap, target string, maxPaths int) Result {
+func MainBfs(ctx context.Context, recipes RecipeMap, tiers TierMap, target string, maxPaths int, opts *SearchOptions) Result {
 	var bfsResult Result
-	cache := &MemoCache{store: make(map[string][]*ElementNode)}
+	bfsResult.TargetElement = target
+	run := newSearchRun(ctx, "bfs", recipes, tiers, opts)
+	cache := newMemoCache()
 	startTime := time.Now()
-	trees := bfsBuildTree(recipes, tiers, target, maxPaths, cache)
+	trees := bfsBuildTree(recipes, tiers, target, run.req.all, 0, maxPaths, cache, run)
 	bfsResult.SearchTime = float64(time.Since(startTime).Microseconds())
 	bfsResult.RecipeTree = flattenTreeList(trees)
-	totalNodes := 0
-	for _, tree := range bfsResult.RecipeTree {
-		totalNodes += countNodes(&tree)
-	}
-	bfsResult.VisitedNodes = totalNodes
+	run.finish(&bfsResult)
 
 	return bfsResult
-} 
\ No newline at end of file
+}
+
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "bfs",
+		Description: "Breadth-first search over recipe combinations, returns up to MaxRecipe trees",
+		MultiTree:   true,
+		Parameters:  []AlgorithmParam{paramMaxRecipe, paramInventory, paramExclude, paramRequire},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainBfs(ctx, recipes, tiers, req.Target, req.MaxRecipe, req.Options)
+	}))
+}
@@ -2,260 +2,334 @@ package cmd
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 )
 
-// Menyimpan state untuk pencarian dua arah
-type BidirectionalState struct {
-	ForwardCache  map[string][]*ElementNode
-	BackwardCache map[string][][]string
-	mu            sync.RWMutex
+// Metrik untuk satu sisi pencarian dua arah
+type SideMetrics struct {
+	// Jumlah lapisan yang diekspansi
+	Layers int `json:"layers"`
+	// Jumlah elemen yang diekspansi oleh sisi ini
+	Expanded int `json:"expanded"`
+	// Jumlah elemen yang dicapai oleh sisi ini
+	Reached int `json:"reached"`
 }
 
-// Inisialisasi state baru
-func NewBidirectionalState() *BidirectionalState {
-	return &BidirectionalState{
-		ForwardCache:  make(map[string][]*ElementNode),
-		BackwardCache: make(map[string][][]string),
-	}
+// Ringkasan pencarian dua arah pada Result
+type BidirectionalReport struct {
+	// Sisi maju: dari target ke bawah
+	Forward SideMetrics `json:"forward"`
+	// Sisi mundur: dari elemen dasar ke atas
+	Backward SideMetrics `json:"backward"`
+	// Elemen pada frontier maju yang sudah dicapai sisi mundur
+	Meeting []string `json:"meeting"`
 }
 
-// Membangun jalur dari elemen dasar ke target
-func generateBackwardPaths(recipes RecipeMap, tiers TierMap, state *BidirectionalState) {
-	queue := make([]string, 0)
-	for el := range abaseElements {
-		queue = append(queue, el)
-		state.mu.Lock()
-		state.BackwardCache[el] = [][]string{{el}}
-		state.mu.Unlock()
+// bidirectionalSearch menyimpan state kedua frontier. Dalam satu putaran kedua
+// sisi berjalan bersamaan dan hanya membaca state sisi lain dari putaran sebelumnya.
+type bidirectionalSearch struct {
+	recipes RecipeMap
+	tiers   TierMap
+	target  string
+	run     *searchRun
+	index   ReverseIndex
+
+	// Sisi maju: indeks resep valid untuk elemen yang sudah diekspansi
+	downRecipes map[string][]int
+	seenDown    map[string]bool
+	frontier    []string
+
+	// Sisi mundur: elemen yang dapat dibuat dari daun
+	reached map[string]bool
+	upLayer []string
+
+	meeting map[string]bool
+	report  BidirectionalReport
+}
+
+func newBidirectionalSearch(recipes RecipeMap, tiers TierMap, index ReverseIndex, target string, run *searchRun) *bidirectionalSearch {
+	s := &bidirectionalSearch{
+		recipes:     recipes,
+		tiers:       tiers,
+		target:      target,
+		run:         run,
+		index:       index,
+		downRecipes: make(map[string][]int),
+		seenDown:    map[string]bool{target: true},
+		frontier:    []string{target},
+		reached:     make(map[string]bool),
+		meeting:     make(map[string]bool),
 	}
 
-	visited := make(map[string]bool)
+	// Elemen awal sisi mundur adalah elemen dasar ditambah inventory pemain
 	for el := range abaseElements {
-		visited[el] = true
+		if !run.opts.Exclude[el] {
+			s.upLayer = append(s.upLayer, el)
+		}
 	}
+	for el := range run.opts.Inventory {
+		if !isBase(el) && !run.opts.Exclude[el] {
+			s.upLayer = append(s.upLayer, el)
+		}
+	}
+	sort.Strings(s.upLayer)
+	for _, el := range s.upLayer {
+		s.reached[el] = true
+	}
+	s.report.Backward.Reached = len(s.upLayer)
+	s.report.Forward.Reached = 1
+	return s
+}
 
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
-		currTier := tiers[curr]
+// stepBackward mengekspansi satu lapisan ke atas: produk dicapai jika kedua bahannya
+// sudah dicapai. Sisi mundur hanya naik ke elemen yang sudah dilihat sisi maju,
+// sehingga pencarian berakhir begitu kedua sisi saling menutupi. Elemen yang baru
+// dilihat sisi maju setelah bahannya dicapai diperiksa saat masuk frontier.
+func (s *bidirectionalSearch) stepBackward() []string {
+	var layer []string
+	fresh := make(map[string]bool)
+	add := func(product string) {
+		if !s.reached[product] && !fresh[product] {
+			fresh[product] = true
+			layer = append(layer, product)
+		}
+	}
 
-		for result, combos := range recipes {
-			resultTier := tiers[result]
-			if currTier >= resultTier {
+	for _, el := range s.upLayer {
+		s.run.metrics.visits.Add(1)
+		s.run.metrics.expansions.Add(1)
+		s.report.Backward.Expanded++
+		for _, edge := range s.index[el] {
+			product := edge.Product
+			if !s.seenDown[product] || s.run.opts.Exclude[product] || !s.reached[edge.Partner] {
 				continue
 			}
-
-			for _, combo := range combos {
-				if combo[0] == curr || combo[1] == curr {
-					var other string
-					if combo[0] == curr {
-						other = combo[1]
-					} else {
-						other = combo[0]
-					}
-
-					if tiers[other] >= resultTier {
-						continue
-					}
-
-					state.mu.RLock()
-					_, otherVisited := state.BackwardCache[other]
-					state.mu.RUnlock()
-
-					if otherVisited {
-						state.mu.Lock()
-						state.BackwardCache[result] = append(state.BackwardCache[result], combo)
-						if !visited[result] {
-							queue = append(queue, result)
-							visited[result] = true
-						}
-						state.mu.Unlock()
-					}
-				}
+			if s.tiers[el] >= s.tiers[product] || s.tiers[edge.Partner] >= s.tiers[product] {
+				s.run.metrics.prunedTier.Add(1)
+				continue
 			}
+			add(product)
 		}
 	}
-}
 
-// Membangun pohon dari target ke elemen dasar
-func forwardBuildTree(
-	recipes RecipeMap,
-	tiers TierMap,
-	target string,
-	maxPaths int,
-	state *BidirectionalState,
-	visitedNodes *int,
-) []*ElementNode {
-	var result []*ElementNode
-
-	if isBase(target) {
-		node := &ElementNode{Result: target}
-		state.mu.Lock()
-		state.ForwardCache[target] = []*ElementNode{node}
-		state.mu.Unlock()
-		*visitedNodes++
-		return []*ElementNode{node}
+	for _, el := range s.frontier {
+		if s.craftable(el) {
+			add(el)
+		}
 	}
+	sort.Strings(layer)
+	return layer
+}
 
-	state.mu.RLock()
-	if cached, ok := state.ForwardCache[target]; ok {
-		state.mu.RUnlock()
-		return cached
+// craftable bernilai true jika elemen yang belum dicapai memiliki resep valid
+// dengan kedua bahan sudah dicapai sisi mundur
+func (s *bidirectionalSearch) craftable(el string) bool {
+	if s.reached[el] || s.run.opts.Exclude[el] {
+		return false
 	}
-	state.mu.RUnlock()
-
-	state.mu.RLock()
-	backwardPaths, exists := state.BackwardCache[target]
-	state.mu.RUnlock()
-
-	if exists {
-		for _, path := range backwardPaths {
-			if len(path) == 1 {
-				result = append(result, &ElementNode{Result: target})
-				*visitedNodes++
-			} else if len(path) == 2 {
-				left, right := path[0], path[1]
-				if tiers[left] >= tiers[target] || tiers[right] >= tiers[target] {
-					continue
-				}
+	for _, combo := range s.recipes[el] {
+		if len(combo) == 2 && s.reached[combo[0]] && s.reached[combo[1]] &&
+			s.tiers[combo[0]] < s.tiers[el] && s.tiers[combo[1]] < s.tiers[el] {
+			return true
+		}
+	}
+	return false
+}
 
-				leftTrees := forwardBuildTree(recipes, tiers, left, maxPaths, state, visitedNodes)
-				rightTrees := forwardBuildTree(recipes, tiers, right, maxPaths, state, visitedNodes)
-
-				for _, l := range leftTrees {
-					for _, r := range rightTrees {
-						if len(result) >= maxPaths {
-							break
-						}
-						result = append(result, &ElementNode{
-							Result:   target,
-							Sources:  path,
-							Children: []*ElementNode{l, r},
-						})
-						*visitedNodes++
-					}
-					if len(result) >= maxPaths {
-						break
-					}
-				}
+// stepForward mengekspansi satu lapisan ke bawah. Daun dan elemen yang sudah atau
+// pada putaran ini dicapai sisi mundur menjadi titik temu dan tidak diekspansi lagi.
+// Elemen baru dikembalikan pada next dan baru ditandai pada seenDown setelah putaran selesai.
+func (s *bidirectionalSearch) stepForward() ([]string, []string) {
+	var next, met []string
+	fresh := make(map[string]bool)
+	for _, el := range s.frontier {
+		s.run.metrics.visits.Add(1)
+		if s.run.opts.isLeaf(el) || s.reached[el] || s.craftable(el) {
+			met = append(met, el)
+			continue
+		}
+		s.run.metrics.expansions.Add(1)
+		s.report.Forward.Expanded++
+
+		parentTier := s.tiers[el]
+		var valid []int
+		for i, combo := range s.recipes[el] {
+			if len(combo) != 2 || s.run.prune(s.recipes, s.tiers, parentTier, combo) {
+				continue
 			}
-			if len(result) >= maxPaths {
-				break
+			valid = append(valid, i)
+			for _, ingredient := range combo {
+				if !s.seenDown[ingredient] && !fresh[ingredient] {
+					fresh[ingredient] = true
+					next = append(next, ingredient)
+				}
 			}
 		}
+		s.downRecipes[el] = valid
+	}
+	return next, met
+}
 
-		if len(result) > 0 {
-			state.mu.Lock()
-			state.ForwardCache[target] = result
-			state.mu.Unlock()
-			return result
+// meet menjalankan kedua sisi sampai setiap elemen pada frontier maju sudah
+// bertemu dengan sisi mundur atau habis diekspansi
+func (s *bidirectionalSearch) meet() {
+	for len(s.frontier) > 0 && !s.run.stopped() {
+		var upLayer, next, met []string
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			upLayer = s.stepBackward()
+		}()
+		go func() {
+			defer wg.Done()
+			next, met = s.stepForward()
+		}()
+		wg.Wait()
+
+		if len(s.upLayer) > 0 || len(upLayer) > 0 {
+			s.report.Backward.Layers++
+			s.run.emitLayer(SideBackward, s.report.Backward.Layers, s.target, s.upLayer, upLayer)
+		}
+		for _, el := range upLayer {
+			s.reached[el] = true
+		}
+		s.upLayer = upLayer
+		s.report.Backward.Reached += len(upLayer)
+
+		s.report.Forward.Layers++
+		s.run.emitLayer(SideForward, s.report.Forward.Layers, s.target, s.frontier, met)
+		s.report.Forward.Reached += len(next)
+		for _, el := range met {
+			s.meeting[el] = true
 		}
+		for _, el := range next {
+			s.seenDown[el] = true
+		}
+		s.frontier = next
 	}
 
-	combos, exists := recipes[target]
-	if !exists {
-		return result
+	s.report.Meeting = make([]string, 0, len(s.meeting))
+	for el := range s.meeting {
+		s.report.Meeting = append(s.report.Meeting, el)
 	}
+	sort.Strings(s.report.Meeting)
+}
 
-	parentTier := tiers[target]
-	queue := make(chan []string, len(combos))
-	resultsChan := make(chan *ElementNode, maxPaths)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var wg sync.WaitGroup
-
-	worker := func() {
-		defer wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case pair, ok := <-queue:
-				if !ok {
-					return
-				}
+// recipesOf mengembalikan resep yang boleh dipakai untuk merangkai elemen. Elemen
+// yang diekspansi sisi maju memakai resep validnya. Titik temu dan elemen di bawahnya
+// memakai semua resep valid: sisi mundur hanya membuktikan elemen tersebut dapat
+// dibuat, resep dengan bahan yang baru dicapai setelah kedua sisi bertemu belum ditemukannya.
+func (s *bidirectionalSearch) recipesOf(element string) [][]string {
+	if valid, ok := s.downRecipes[element]; ok {
+		combos := make([][]string, len(valid))
+		for i, idx := range valid {
+			combos[i] = s.recipes[element][idx]
+		}
+		return combos
+	}
 
-				if isUnbuildable(pair[0], recipes) || isUnbuildable(pair[1], recipes) {
-					continue
-				}
+	s.run.metrics.expansions.Add(1)
+	parentTier := s.tiers[element]
+	var combos [][]string
+	for _, combo := range s.recipes[element] {
+		if len(combo) != 2 || s.run.prune(s.recipes, s.tiers, parentTier, combo) {
+			continue
+		}
+		combos = append(combos, combo)
+	}
+	return combos
+}
 
-				if tiers[pair[0]] >= parentTier || tiers[pair[1]] >= parentTier {
-					continue
-				}
+// build merangkai pohon dari target ke daun memakai resep yang ditemukan kedua sisi
+func (s *bidirectionalSearch) build(target string, need uint64, depth int, maxPaths int, cache *MemoCache) []*ElementNode {
+	run := s.run
+	run.metrics.visits.Add(1)
 
-				leftTrees := forwardBuildTree(recipes, tiers, pair[0], maxPaths, state, visitedNodes)
-				rightTrees := forwardBuildTree(recipes, tiers, pair[1], maxPaths, state, visitedNodes)
-
-				for _, l := range leftTrees {
-					for _, r := range rightTrees {
-						select {
-						case resultsChan <- &ElementNode{
-							Result:   target,
-							Sources:  pair,
-							Children: []*ElementNode{l, r},
-						}:
-							*visitedNodes++
-						case <-ctx.Done():
-							return
-						}
-					}
-				}
-			}
+	// Elemen wajib yang masih harus muncul di bawah target
+	need = run.req.remaining(target, need)
+	key := run.req.cacheKey(target, need)
+
+	if run.opts.isLeaf(target) {
+		if need != 0 {
+			return nil
 		}
+		return []*ElementNode{{Result: target}}
 	}
 
-	workerCount := 2 + parentTier*2
-	if workerCount > 16 {
-		workerCount = 16
+	if cached, ok := cache.get(key, maxPaths); ok {
+		run.metrics.memoHits.Add(1)
+		return cached
 	}
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go worker()
+
+	if run.stopped() {
+		return nil
 	}
 
-	go func() {
-		for _, combo := range combos {
-			queue <- combo
-		}
-		close(queue)
-	}()
+	combos := s.recipesOf(target)
+	if len(combos) == 0 {
+		return nil
+	}
 
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	for node := range resultsChan {
-		result = append(result, node)
-		if len(result) >= maxPaths {
-			cancel()
-			break
+	run.emit(EventExpand, target, depth, nil, 0)
+
+	expand := func(ctx context.Context, pair []string) []*ElementNode {
+		nodes := expandRequired(ctx, run, target, pair, need, maxPaths, func(element string, need uint64, limit int) []*ElementNode {
+			return s.build(element, need, depth+1, limit, cache)
+		})
+		if len(nodes) > 0 {
+			run.emit(EventRecipe, target, depth, pair, len(nodes))
 		}
+		return nodes
+	}
+
+	workerCount := 2 + s.tiers[target]*2
+	if workerCount > 16 {
+		workerCount = 16
 	}
+	result := combineParallel(combos, workerCount, maxPaths, run, expand)
+	run.emit(EventComplete, target, depth, nil, len(result))
 
-	state.mu.Lock()
-	state.ForwardCache[target] = result
-	state.mu.Unlock()
+	cache.put(key, result, maxPaths)
 
 	return result
 }
 
-func MainBidirectionalBfs(recipes RecipeMap, tiers TierMap, target string, maxPaths int) Result {
+// MainBidirectionalBfs menjalankan frontier maju dari target dan frontier mundur dari
+// elemen dasar secara bersamaan, lalu merangkai pohon dari himpunan titik temu.
+// index boleh nil, dalam hal ini indeks balik dibangun untuk pencarian ini saja.
+func MainBidirectionalBfs(ctx context.Context, recipes RecipeMap, tiers TierMap, index ReverseIndex, target string, maxPaths int, opts *SearchOptions) Result {
 	var res Result
-
-	state := NewBidirectionalState()
-	visited := 0
-
-	generateBackwardPaths(recipes, tiers, state)
+	res.TargetElement = target
+	run := newSearchRun(ctx, "bidirectional", recipes, tiers, opts)
 
 	start := time.Now()
-	trees := forwardBuildTree(recipes, tiers, target, maxPaths, state, &visited)
+	if index == nil {
+		index = BuildReverseIndex(recipes, tiers)
+	}
+	search := newBidirectionalSearch(recipes, tiers, index, target, run)
+	search.meet()
+
+	cache := newMemoCache()
+	trees := search.build(target, run.req.all, 0, maxPaths, cache)
 	res.SearchTime = float64(time.Since(start).Microseconds())
 	res.RecipeTree = flattenTreeList(trees)
-	res.VisitedNodes = visited
+	res.Bidirectional = &search.report
+	run.finish(&res)
 
 	return res
-}
\ No newline at end of file
+}
+
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "bidirectional",
+		Description: "Meet-in-the-middle search from the target and from the base elements, returns up to MaxRecipe trees",
+		MultiTree:   true,
+		Parameters:  []AlgorithmParam{paramMaxRecipe, paramInventory, paramExclude, paramRequire},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainBidirectionalBfs(ctx, recipes, tiers, req.Index, req.Target, req.MaxRecipe, req.Options)
+	}))
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+// Resep y+earth untuk m baru ditemukan sisi mundur setelah frontier maju
+// berhenti di m, sehingga m harus tetap dirangkai dari kedua resepnya
+func TestBidirectionalMatchesBfs(t *testing.T) {
+	recipes := RecipeMap{
+		"water": nil, "fire": nil, "earth": nil, "air": nil,
+		"x": {{"earth", "air"}},
+		"y": {{"x", "x"}},
+		"m": {{"water", "fire"}, {"y", "earth"}},
+		"t": {{"m", "air"}},
+	}
+	tiers := TierMap{
+		"water": 0, "fire": 0, "earth": 0, "air": 0,
+		"x": 1, "y": 2, "m": 3, "t": 4,
+	}
+
+	for _, target := range []string{"m", "t"} {
+		for _, maxRecipe := range []int{1, 2, 10} {
+			want := MainBfs(context.Background(), recipes, tiers, target, maxRecipe, nil)
+			got := MainBidirectionalBfs(context.Background(), recipes, tiers, nil, target, maxRecipe, nil)
+
+			if len(got.RecipeTree) != len(want.RecipeTree) {
+				t.Errorf("%s MaxRecipe %d: bidirectional returned %d trees, bfs %d",
+					target, maxRecipe, len(got.RecipeTree), len(want.RecipeTree))
+				continue
+			}
+			wantSet := make(map[string]bool)
+			for i := range want.RecipeTree {
+				wantSet[TreeSignature(&want.RecipeTree[i])] = true
+			}
+			for i := range got.RecipeTree {
+				if sig := TreeSignature(&got.RecipeTree[i]); !wantSet[sig] {
+					t.Errorf("%s MaxRecipe %d: tree %s not returned by bfs", target, maxRecipe, sig)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DatasetVersion menghitung versi dataset dari isinya, sehingga dataset yang
+// sama selalu memiliki versi yang sama
+func DatasetVersion(recipes RecipeMap, tiers TierMap) string {
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%d\x00", name, tiers[name])
+		for _, combo := range recipes[name] {
+			fmt.Fprintf(h, "%s\x00", strings.Join(combo, "+"))
+		}
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Kunci cache hasil pencarian. Semua parameter yang memengaruhi hasil harus masuk ke kunci.
+type CacheKey struct {
+	Version       string
+	Algorithm     string
+	Target        string
+	MaxRecipe     int
+	CostMode      string
+	DepthLimit    int
+	Deterministic bool
+	Inventory     []string
+	Exclude       []string
+	Require       []string
+}
+
+// String menyerialisasi kunci. Urutan Inventory dan Exclude tidak berpengaruh
+// sehingga diurutkan, sedangkan urutan Require dipertahankan karena menentukan bitmask.
+func (k CacheKey) String() string {
+	sorted := func(list []string) string {
+		list = slices.Clone(list)
+		sort.Strings(list)
+		return strings.Join(list, ",")
+	}
+	return strings.Join([]string{
+		k.Version, k.Algorithm, k.Target,
+		fmt.Sprint(k.MaxRecipe), k.CostMode, fmt.Sprint(k.DepthLimit), fmt.Sprint(k.Deterministic),
+		sorted(k.Inventory), sorted(k.Exclude), strings.Join(k.Require, ","),
+	}, "\x00")
+}
+
+// Statistik cache hasil
+type CacheStats struct {
+	Entries    int     `json:"entries"`
+	Bytes      int64   `json:"bytes"`
+	MaxEntries int     `json:"maxEntries"`
+	MaxBytes   int64   `json:"maxBytes"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Evictions  int64   `json:"evictions"`
+	HitRate    float64 `json:"hitRate"`
+}
+
+type cacheEntry struct {
+	key    string
+	result Result
+	size   int64
+}
+
+// ResultCache adalah cache LRU hasil pencarian yang dipakai bersama oleh semua request.
+// Ukuran dibatasi oleh jumlah entri dan perkiraan memori; nilai 0 berarti tanpa batas
+// untuk batas tersebut. Result yang disimpan tidak boleh diubah oleh pemanggil.
+type ResultCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	lru        *list.List
+	items      map[string]*list.Element
+	bytes      int64
+	hits       int64
+	misses     int64
+	evictions  int64
+}
+
+func NewResultCache(maxEntries int, maxBytes int64) *ResultCache {
+	return &ResultCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		lru:        list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get mengambil hasil dari cache dan menandainya sebagai yang terakhir dipakai
+func (c *ResultCache) Get(key CacheKey) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key.String()]
+	if !ok {
+		c.misses++
+		return Result{}, false
+	}
+	c.hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).result, true
+}
+
+// Add menyimpan hasil lalu membuang entri paling lama tidak dipakai sampai
+// batas terpenuhi. Hasil yang lebih besar dari batas memori tidak disimpan.
+func (c *ResultCache) Add(key CacheKey, result Result) {
+	size := estimateResultSize(result)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := key.String()
+	if elem, ok := c.items[k]; ok {
+		c.removeElement(elem)
+	}
+	c.items[k] = c.lru.PushFront(&cacheEntry{key: k, result: result, size: size})
+	c.bytes += size
+
+	for (c.maxEntries > 0 && c.lru.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.removeElement(c.lru.Back())
+		c.evictions++
+	}
+}
+
+func (c *ResultCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+}
+
+// Purge mengosongkan cache, dipakai saat dataset diganti
+func (c *ResultCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+func (c *ResultCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Entries:    c.lru.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// Perkiraan ukuran satu ElementNode beserta slice-nya di memori
+const nodeOverhead = 128
+
+// estimateResultSize memperkirakan memori yang ditahan Result. Subpohon yang
+// dipakai bersama hanya dihitung sekali.
+func estimateResultSize(result Result) int64 {
+	seen := make(map[*ElementNode]bool)
+	var size int64
+
+	var walk func(node *ElementNode)
+	walk = func(node *ElementNode) {
+		if node == nil || seen[node] {
+			return
+		}
+		seen[node] = true
+		size += nodeOverhead + int64(len(node.Result))
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for i := range result.RecipeTree {
+		size += nodeOverhead + int64(len(result.RecipeTree[i].Result))
+		for _, child := range result.RecipeTree[i].Children {
+			walk(child)
+		}
+	}
+	for _, list := range result.ToCraft {
+		for _, name := range list {
+			size += int64(len(name)) + 16
+		}
+	}
+	return size + 512
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"math/big"
+	"sort"
+)
+
+// Ringkasan elemen untuk katalog
+type ElementSummary struct {
+	Name        string `json:"name"`
+	Tier        int    `json:"tier"`
+	RecipeCount int    `json:"recipeCount"`
+	TreeCount   string `json:"treeCount"`
+}
+
+// Detail satu elemen beserta seluruh resep mentahnya
+type ElementDetail struct {
+	Name      string     `json:"name"`
+	Tier      int        `json:"tier"`
+	IsBase    bool       `json:"isBase"`
+	TreeCount string     `json:"treeCount"`
+	Recipes   [][]string `json:"recipes"`
+}
+
+// Resep lain yang memakai suatu elemen sebagai bahan
+type ElementUsage struct {
+	Product     string `json:"product"`
+	ProductTier int    `json:"productTier"`
+	Partner     string `json:"partner"`
+}
+
+// treeCountString mengambil jumlah pohon resep dari hasil CountRecipeTrees
+func treeCountString(counts map[string]*big.Int, name string) string {
+	if count, ok := counts[name]; ok {
+		return count.String()
+	}
+	return "0"
+}
+
+// ListElements mengembalikan seluruh elemen terurut berdasarkan tier lalu nama
+func ListElements(recipes RecipeMap, tiers TierMap, counts map[string]*big.Int) []ElementSummary {
+	list := make([]ElementSummary, 0, len(recipes))
+	for name, combos := range recipes {
+		list = append(list, ElementSummary{
+			Name:        name,
+			Tier:        tiers[name],
+			RecipeCount: len(combos),
+			TreeCount:   treeCountString(counts, name),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Tier != list[j].Tier {
+			return list[i].Tier < list[j].Tier
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// GetElement mengembalikan detail elemen, ok bernilai false jika elemen tidak ada
+func GetElement(recipes RecipeMap, tiers TierMap, counts map[string]*big.Int, name string) (ElementDetail, bool) {
+	combos, ok := recipes[name]
+	if !ok {
+		return ElementDetail{}, false
+	}
+	if combos == nil {
+		combos = [][]string{}
+	}
+	return ElementDetail{
+		Name:      name,
+		Tier:      tiers[name],
+		IsBase:    isBase(name),
+		TreeCount: treeCountString(counts, name),
+		Recipes:   combos,
+	}, true
+}
+
+// UsedIn mencari semua resep yang memakai element sebagai bahan
+func UsedIn(index ReverseIndex, tiers TierMap, element string) []ElementUsage {
+	usages := make([]ElementUsage, 0, len(index[element]))
+	for _, edge := range index[element] {
+		usages = append(usages, ElementUsage{Product: edge.Product, ProductTier: tiers[edge.Product], Partner: edge.Partner})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].ProductTier != usages[j].ProductTier {
+			return usages[i].ProductTier < usages[j].ProductTier
+		}
+		if usages[i].Product != usages[j].Product {
+			return usages[i].Product < usages[j].Product
+		}
+		return usages[i].Partner < usages[j].Partner
+	})
+	return usages
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// Hasil satu algoritma pada perbandingan
+type ComparedResult struct {
+	Algorithm string `json:"algorithm"`
+	Result    Result `json:"result"`
+	// Jumlah pohon berbeda yang ditemukan algoritma ini
+	DistinctTrees int `json:"distinctTrees"`
+	// Pohon yang tidak ditemukan oleh algoritma lain mana pun
+	UniqueTrees int `json:"uniqueTrees"`
+}
+
+// Perbandingan hasil beberapa algoritma untuk target dan MaxRecipe yang sama
+type Comparison struct {
+	Results []ComparedResult `json:"results"`
+	// SameTrees bernilai true jika semua algoritma menemukan himpunan pohon yang sama
+	SameTrees bool `json:"sameTrees"`
+	// Jumlah pohon yang ditemukan oleh semua algoritma
+	CommonTrees int `json:"commonTrees"`
+}
+
+// TreeSignature menyerialisasi pohon secara kanonik sehingga a+b dan b+a
+// dengan subpohon yang sama menghasilkan signature yang sama
+func TreeSignature(node *ElementNode) string {
+	return treeSignature(node, true)
+}
+
+// treeSignature menyerialisasi pohon, urutan anak dipertahankan jika unordered false
+func treeSignature(node *ElementNode, unordered bool) string {
+	if node == nil {
+		return ""
+	}
+	if len(node.Children) == 0 {
+		return node.Result
+	}
+	children := make([]string, len(node.Children))
+	for i, child := range node.Children {
+		children[i] = treeSignature(child, unordered)
+	}
+	if unordered {
+		sort.Strings(children)
+	}
+	return node.Result + "(" + strings.Join(children, ",") + ")"
+}
+
+func treeSet(result Result) map[string]bool {
+	set := make(map[string]bool, len(result.RecipeTree))
+	for i := range result.RecipeTree {
+		set[TreeSignature(&result.RecipeTree[i])] = true
+	}
+	return set
+}
+
+// CompareResults membandingkan himpunan pohon dari hasil beberapa algoritma.
+// Hasil harus masih dalam format tree.
+func CompareResults(algorithms []string, results []Result) Comparison {
+	sets := make([]map[string]bool, len(results))
+	found := make(map[string]int)
+	for i, result := range results {
+		sets[i] = treeSet(result)
+		for sig := range sets[i] {
+			found[sig]++
+		}
+	}
+
+	comparison := Comparison{Results: make([]ComparedResult, len(results)), SameTrees: true}
+	for _, n := range found {
+		if n == len(results) {
+			comparison.CommonTrees++
+		} else {
+			comparison.SameTrees = false
+		}
+	}
+	for i, result := range results {
+		unique := 0
+		for sig := range sets[i] {
+			if found[sig] == 1 && len(results) > 1 {
+				unique++
+			}
+		}
+		comparison.Results[i] = ComparedResult{
+			Algorithm:     algorithms[i],
+			Result:        result,
+			DistinctTrees: len(sets[i]),
+			UniqueTrees:   unique,
+		}
+	}
+	return comparison
+}
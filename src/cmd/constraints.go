@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"math/bits"
+	"strconv"
+)
+
+// Jumlah maksimum elemen wajib dalam satu request. Elemen wajib yang bisa muncul
+// di kedua bahan dicoba di setiap sisi, sehingga biayanya tumbuh 2^k.
+const MaxRequiredElements = 8
+
+// requirements melacak elemen wajib sebagai bitmask. reach[e] berisi elemen wajib
+// yang mungkin muncul di salah satu pohon resep e, sehingga kombinasi yang tidak
+// mungkin memuat elemen wajib bisa dipangkas sebelum diekspansi. room[e] adalah
+// batas atas jumlah elemen wajib yang dapat dimuat satu pohon e.
+type requirements struct {
+	bit   map[string]uint64
+	all   uint64
+	reach map[string]uint64
+	room  map[string]int
+}
+
+func newRequirements(recipes RecipeMap, tiers TierMap, opts *SearchOptions) *requirements {
+	req := &requirements{bit: make(map[string]uint64, len(opts.Require))}
+	for i, el := range opts.Require {
+		if i >= MaxRequiredElements {
+			break
+		}
+		req.bit[el] |= 1 << uint(i)
+		req.all |= 1 << uint(i)
+	}
+	if req.all == 0 {
+		return req
+	}
+
+	req.reach = make(map[string]uint64, len(recipes))
+	req.room = make(map[string]int, len(recipes))
+	for _, element := range elementsByTier(recipes, tiers) {
+		if opts.Exclude[element] {
+			continue
+		}
+		mask := req.bit[element]
+		room := 0
+		if !opts.isLeaf(element) {
+			parentTier := tiers[element]
+			for _, combo := range recipes[element] {
+				if len(combo) != 2 {
+					continue
+				}
+				if opts.isUnbuildable(combo[0], recipes) || opts.isUnbuildable(combo[1], recipes) {
+					continue
+				}
+				if tiers[combo[0]] >= parentTier || tiers[combo[1]] >= parentTier {
+					continue
+				}
+				mask |= req.reach[combo[0]] | req.reach[combo[1]]
+				room = max(room, req.room[combo[0]]+req.room[combo[1]])
+			}
+		}
+		req.reach[element] = mask
+		req.room[element] = min(bits.OnesCount64(req.bit[element])+room, bits.OnesCount64(mask))
+	}
+	return req
+}
+
+// fits bernilai false jika tidak ada pohon element yang dapat memuat semua elemen pada need
+func (req *requirements) fits(element string, need uint64) bool {
+	return need == 0 || bits.OnesCount64(need) <= req.room[element]
+}
+
+// cacheKey membedakan entri memo untuk elemen yang sama dengan kebutuhan berbeda
+func (req *requirements) cacheKey(target string, need uint64) string {
+	if need == 0 {
+		return target
+	}
+	return target + "#" + strconv.FormatUint(need, 16)
+}
+
+// remaining menghapus target sendiri dari kebutuhan
+func (req *requirements) remaining(target string, need uint64) uint64 {
+	return need &^ req.bit[target]
+}
+
+// split membagi kebutuhan ke bahan kiri dan kanan. Elemen wajib yang hanya bisa
+// muncul di satu sisi dipaksakan ke sisi tersebut, yang bisa muncul di keduanya
+// dikembalikan sebagai either dan dibagi oleh expandRequired.
+// ok bernilai false jika ada elemen wajib yang tidak mungkin muncul.
+func (req *requirements) split(pair []string, need uint64) (left, right, either uint64, ok bool) {
+	for need != 0 {
+		b := uint64(1) << uint(bits.TrailingZeros64(need))
+		need &^= b
+		inLeft := req.reach[pair[0]]&b != 0
+		inRight := req.reach[pair[1]]&b != 0
+		switch {
+		case inLeft && inRight:
+			either |= b
+		case inLeft:
+			left |= b
+		case inRight:
+			right |= b
+		default:
+			return 0, 0, 0, false
+		}
+	}
+	if !req.fits(pair[0], left) || !req.fits(pair[1], right) ||
+		bits.OnesCount64(left|right|either) > req.room[pair[0]]+req.room[pair[1]] {
+		return 0, 0, 0, false
+	}
+	return left, right, either, true
+}
+
+// expandRequired merangkai paling banyak maxPaths pohon target dari satu kombinasi
+// bahan yang memuat semua elemen wajib pada need. build membangun paling banyak limit
+// pohon bahan untuk kebutuhan tertentu. Setiap pembagian elemen either ke kiri dan
+// ke kanan dicoba berurutan, sehingga pohon anak selalu dibangun dengan kebutuhannya
+// sendiri dan daftar yang sudah terpotong maxPaths tidak perlu disaring ulang.
+func expandRequired(ctx context.Context, run *searchRun, target string, pair []string, need uint64, maxPaths int, build treeBuilder) []*ElementNode {
+	leftNeed, rightNeed, either, ok := run.req.split(pair, need)
+	if !ok {
+		run.metrics.prunedConstraint.Add(1)
+		return nil
+	}
+	if either == 0 {
+		leftTrees, rightTrees := buildPair(pair, leftNeed, rightNeed, maxPaths, build)
+		return expandPair(ctx, run, target, pair, leftTrees, rightTrees, maxPaths)
+	}
+
+	// Pohon yang memuat elemen wajib di kedua sisi muncul pada lebih dari satu pembagian
+	var nodes []*ElementNode
+	seen := make(map[string]bool)
+	for sub := either; ; sub = (sub - 1) & either {
+		if run.stopped() || ctx.Err() != nil {
+			break
+		}
+		// Pembagian yang melebihi daya tampung salah satu bahan dilewati tanpa rekursi
+		if !run.req.fits(pair[0], leftNeed|sub) || !run.req.fits(pair[1], rightNeed|(either&^sub)) {
+			run.metrics.prunedConstraint.Add(1)
+			if sub == 0 {
+				break
+			}
+			continue
+		}
+		leftTrees, rightTrees := buildPair(pair, leftNeed|sub, rightNeed|(either&^sub), maxPaths, build)
+		for _, node := range expandPair(ctx, run, target, pair, leftTrees, rightTrees, maxPaths) {
+			if sig := treeSignature(node, false); !seen[sig] && len(nodes) < maxPaths {
+				seen[sig] = true
+				nodes = append(nodes, node)
+			}
+		}
+		if sub == 0 || len(nodes) >= maxPaths {
+			break
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// constraintTestData berisi elemen wajib r yang bisa muncul di kedua bahan t,
+// elemen s yang muncul di beberapa tier, dan resep a+a
+func constraintTestData() (RecipeMap, TierMap) {
+	recipes := RecipeMap{
+		"water": nil, "fire": nil, "earth": nil, "air": nil,
+		"r": {{"earth", "air"}, {"water", "water"}},
+		"s": {{"fire", "air"}},
+		"a": {{"water", "fire"}, {"r", "water"}, {"s", "earth"}},
+		"b": {{"water", "earth"}, {"r", "fire"}, {"s", "r"}},
+		"c": {{"a", "a"}, {"a", "s"}, {"r", "b"}},
+		"t": {{"a", "b"}, {"c", "r"}, {"b", "c"}},
+	}
+	tiers := TierMap{
+		"water": 0, "fire": 0, "earth": 0, "air": 0,
+		"r": 1, "s": 1, "a": 2, "b": 2, "c": 3, "t": 4,
+	}
+	return recipes, tiers
+}
+
+// randomConstraintData membangun dataset acak berlapis dengan seed tetap
+func randomConstraintData(seed int64, count int) (RecipeMap, TierMap) {
+	rng := rand.New(rand.NewSource(seed))
+	recipes := RecipeMap{"water": nil, "fire": nil, "earth": nil, "air": nil}
+	tiers := TierMap{"water": 0, "fire": 0, "earth": 0, "air": 0}
+	names := []string{"water", "fire", "earth", "air"}
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("e%d", i)
+		tier := 1 + i/4
+		lower := 0
+		for lower < len(names) && tiers[names[lower]] < tier {
+			lower++
+		}
+		seen := make(map[string]bool)
+		for j := 0; j < 1+rng.Intn(3); j++ {
+			a, b := names[rng.Intn(lower)], names[rng.Intn(lower)]
+			if a > b {
+				a, b = b, a
+			}
+			if !seen[a+"+"+b] {
+				seen[a+"+"+b] = true
+				recipes[name] = append(recipes[name], []string{a, b})
+			}
+		}
+		tiers[name] = tier
+		names = append(names, name)
+	}
+	return recipes, tiers
+}
+
+// allowed mengembalikan true jika pohon memuat semua elemen require dan tidak memuat exclude
+func allowed(node *ElementNode, require, exclude []string) bool {
+	found := make(map[string]bool)
+	var walk func(n *ElementNode)
+	walk = func(n *ElementNode) {
+		found[n.Result] = true
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	for _, el := range require {
+		if !found[el] {
+			return false
+		}
+	}
+	for _, el := range exclude {
+		if found[el] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConstraints membandingkan setiap algoritma dengan pencarian tanpa batasan
+// yang hasilnya disaring: jumlah pohon harus min(maxRecipe, jumlah yang lolos),
+// setiap pohon harus lolos saringan dan tidak ada pohon ganda
+func checkConstraints(t *testing.T, recipes RecipeMap, tiers TierMap, target string, require, exclude []string) {
+	t.Helper()
+	all := MainBfs(context.Background(), recipes, tiers, target, MaxRecipeLimit, nil)
+	if all.Truncated || len(all.RecipeTree) >= MaxRecipeLimit {
+		t.Fatalf("%s: unconstrained search is too large for the test", target)
+	}
+	want := make(map[string]bool)
+	for i := range all.RecipeTree {
+		if allowed(&all.RecipeTree[i], require, exclude) {
+			want[treeSignature(&all.RecipeTree[i], false)] = true
+		}
+	}
+
+	searches := map[string]func(maxRecipe int, opts *SearchOptions) Result{
+		"bfs": func(maxRecipe int, opts *SearchOptions) Result {
+			return MainBfs(context.Background(), recipes, tiers, target, maxRecipe, opts)
+		},
+		"dfs": func(maxRecipe int, opts *SearchOptions) Result {
+			return MainDfs(context.Background(), recipes, tiers, target, maxRecipe, 0, opts)
+		},
+		"iddfs": func(maxRecipe int, opts *SearchOptions) Result {
+			return MainIddfs(context.Background(), recipes, tiers, target, maxRecipe, 0, opts)
+		},
+		"bidirectional": func(maxRecipe int, opts *SearchOptions) Result {
+			return MainBidirectionalBfs(context.Background(), recipes, tiers, nil, target, maxRecipe, opts)
+		},
+	}
+	for name, search := range searches {
+		for _, maxRecipe := range []int{1, 2, 5, MaxRecipeLimit} {
+			opts := DefaultSearchOptions()
+			opts.Require = require
+			if len(exclude) > 0 {
+				opts.Exclude = make(map[string]bool)
+				for _, el := range exclude {
+					opts.Exclude[el] = true
+				}
+			}
+			res := search(maxRecipe, opts)
+
+			expected := min(len(want), maxRecipe)
+			if len(res.RecipeTree) != expected {
+				t.Errorf("%s %s require %v exclude %v MaxRecipe %d: got %d trees, want %d",
+					name, target, require, exclude, maxRecipe, len(res.RecipeTree), expected)
+			}
+			seen := make(map[string]bool)
+			for i := range res.RecipeTree {
+				sig := treeSignature(&res.RecipeTree[i], false)
+				if !want[sig] {
+					t.Errorf("%s %s require %v exclude %v: tree %s violates the constraints", name, target, require, exclude, sig)
+				}
+				if seen[sig] {
+					t.Errorf("%s %s require %v exclude %v: tree %s returned twice", name, target, require, exclude, sig)
+				}
+				seen[sig] = true
+			}
+		}
+	}
+}
+
+func TestConstraintsMatchFilteredSearch(t *testing.T) {
+	recipes, tiers := constraintTestData()
+	cases := []struct {
+		require, exclude []string
+	}{
+		{require: []string{"r"}},
+		{require: []string{"s"}},
+		{require: []string{"r", "s"}},
+		{require: []string{"r", "s", "c"}},
+		{require: []string{"a", "b"}},
+		{exclude: []string{"r"}},
+		{exclude: []string{"a"}},
+		{require: []string{"s"}, exclude: []string{"r"}},
+		{require: []string{"r"}, exclude: []string{"s", "fire"}},
+	}
+	for _, target := range []string{"b", "c", "t"} {
+		for _, tc := range cases {
+			checkConstraints(t, recipes, tiers, target, tc.require, tc.exclude)
+		}
+	}
+}
+
+func TestConstraintsMatchFilteredSearchRandom(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		recipes, tiers := randomConstraintData(seed, 16)
+		checkConstraints(t, recipes, tiers, "e15", []string{"e3"}, nil)
+		checkConstraints(t, recipes, tiers, "e15", []string{"e2", "e6", "fire"}, nil)
+		checkConstraints(t, recipes, tiers, "e15", []string{"e5"}, []string{"e1"})
+		checkConstraints(t, recipes, tiers, "e15", nil, []string{"e4", "e7"})
+	}
+}
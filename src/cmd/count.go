@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// countRecipeTrees menghitung jumlah pohon resep berbeda untuk setiap elemen.
+// Aturan yang dipakai sama dengan pencarian: bahan harus dapat dibuat dan
+// bertier lebih rendah dari produknya. Urutan bahan tidak membedakan pohon, sama
+// seperti TreeSignature: resep a+b dan b+a dihitung sekali, dan resep a+a dengan n
+// pohon untuk a menghasilkan n*(n+1)/2 pohon karena (t1,t2) sama dengan (t2,t1).
+func countRecipeTrees(ctx context.Context, recipes RecipeMap, tiers TierMap, opts *SearchOptions, metrics *SearchMetrics) map[string]*big.Int {
+	counts := make(map[string]*big.Int, len(recipes))
+	for el := range abaseElements {
+		counts[el] = big.NewInt(1)
+	}
+	for el := range opts.Inventory {
+		counts[el] = big.NewInt(1)
+	}
+	for el := range opts.Exclude {
+		delete(counts, el)
+	}
+
+	for _, element := range elementsByTier(recipes, tiers) {
+		if ctx.Err() != nil {
+			break
+		}
+		metrics.visits.Add(1)
+		if opts.isLeaf(element) || opts.Exclude[element] {
+			continue
+		}
+		metrics.expansions.Add(1)
+
+		parentTier := tiers[element]
+		total := new(big.Int)
+		seen := make(map[string]bool)
+
+		for _, combo := range recipes[element] {
+			if len(combo) != 2 {
+				continue
+			}
+			if opts.isUnbuildable(combo[0], recipes) || opts.isUnbuildable(combo[1], recipes) {
+				metrics.prunedUnbuildable.Add(1)
+				continue
+			}
+			if tiers[combo[0]] >= parentTier || tiers[combo[1]] >= parentTier {
+				metrics.prunedTier.Add(1)
+				continue
+			}
+
+			a, b := combo[0], combo[1]
+			if a > b {
+				a, b = b, a
+			}
+			if seen[a+"+"+b] {
+				continue
+			}
+			seen[a+"+"+b] = true
+
+			left, okL := counts[combo[0]]
+			right, okR := counts[combo[1]]
+			if !okL || !okR {
+				continue
+			}
+			if combo[0] == combo[1] {
+				pairs := new(big.Int).Add(left, big.NewInt(1))
+				pairs.Mul(pairs, left)
+				total.Add(total, pairs.Rsh(pairs, 1))
+			} else {
+				total.Add(total, new(big.Int).Mul(left, right))
+			}
+		}
+
+		counts[element] = total
+	}
+
+	return counts
+}
+
+// CountRecipeTrees mengembalikan jumlah pohon resep berbeda untuk seluruh elemen
+func CountRecipeTrees(recipes RecipeMap, tiers TierMap) map[string]*big.Int {
+	return countRecipeTrees(context.Background(), recipes, tiers, DefaultSearchOptions(), new(SearchMetrics))
+}
+
+// MainCount menghitung jumlah pohon resep berbeda untuk target tanpa membangun pohonnya
+func MainCount(ctx context.Context, recipes RecipeMap, tiers TierMap, target string, opts *SearchOptions) Result {
+	result := Result{TargetElement: target, RecipeTree: []ElementNode{}}
+	run := newSearchRun(ctx, "count", recipes, tiers, opts)
+	startTime := time.Now()
+
+	counts := countRecipeTrees(run.ctx, recipes, tiers, run.opts, &run.metrics)
+	if count, ok := counts[target]; ok {
+		result.TreeCount = count.String()
+	}
+
+	result.SearchTime = float64(time.Since(startTime).Microseconds())
+	run.finish(&result)
+	return result
+}
+
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "count",
+		Description: "Exact number of distinct recipe trees for the target, without building them",
+		Parameters:  []AlgorithmParam{paramInventory, paramExclude},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainCount(ctx, recipes, tiers, req.Target, req.Options)
+	}))
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import "sort"
+
+// Elemen yang dapat dibuat dari kumpulan elemen yang dimiliki
+type CraftableElement struct {
+	Name   string   `json:"name"`
+	Tier   int      `json:"tier"`
+	Recipe []string `json:"recipe"`
+	// Langkah ke berapa elemen ini dapat dibuat, 1 berarti langsung dari elemen yang dimiliki
+	Step int `json:"step"`
+}
+
+// Craftable mencari elemen yang dapat dibuat dari owned dalam paling banyak maxSteps langkah.
+// Setiap langkah boleh memakai elemen yang dimiliki maupun hasil langkah sebelumnya.
+// Berbeda dengan pencarian pohon, aturan tier tidak dipakai karena di dalam permainan
+// semua resep dapat digunakan selama kedua bahannya tersedia.
+//
+// Elemen yang baru dapat dibuat pada suatu langkah selalu memakai setidaknya satu
+// elemen yang baru tersedia pada langkah sebelumnya, sehingga kandidatnya cukup
+// dicari lewat indeks balik dari elemen-elemen tersebut.
+func Craftable(recipes RecipeMap, tiers TierMap, index ReverseIndex, owned []string, maxSteps int) []CraftableElement {
+	available := make(map[string]bool, len(owned))
+	for _, el := range owned {
+		available[el] = true
+	}
+
+	craftable := []CraftableElement{}
+	fresh := owned
+
+	for step := 1; step <= maxSteps && len(fresh) > 0; step++ {
+		candidates := make(map[string]bool)
+		for _, el := range fresh {
+			for _, edge := range index[el] {
+				if !available[edge.Product] && available[edge.Partner] {
+					candidates[edge.Product] = true
+				}
+			}
+		}
+
+		found := make([]CraftableElement, 0, len(candidates))
+		for element := range candidates {
+			// Resep yang dilaporkan adalah resep pertama yang bahannya tersedia
+			for _, combo := range recipes[element] {
+				if len(combo) == 2 && available[combo[0]] && available[combo[1]] {
+					found = append(found, CraftableElement{
+						Name:   element,
+						Tier:   tiers[element],
+						Recipe: combo,
+						Step:   step,
+					})
+					break
+				}
+			}
+		}
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].Tier != found[j].Tier {
+				return found[i].Tier < found[j].Tier
+			}
+			return found[i].Name < found[j].Name
+		})
+
+		// Elemen baru baru bisa dipakai pada langkah berikutnya
+		fresh = fresh[:0:0]
+		for _, el := range found {
+			available[el.Name] = true
+			fresh = append(fresh, el.Name)
+		}
+		craftable = append(craftable, found...)
+	}
+
+	return craftable
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Node pada format DAG, anak dirujuk melalui id
+type DagNode struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Sources  []string `json:"sources"`
+	Children []int    `json:"children"`
+}
+
+// Kumpulan pohon resep yang subpohon identiknya hanya dikirim sekali
+type RecipeDag struct {
+	Nodes []DagNode `json:"nodes"`
+	// Id node akar untuk setiap pohon, sesuai urutan pohon pada hasil pencarian
+	Roots []int `json:"roots"`
+}
+
+// BuildRecipeDag menggabungkan subpohon yang identik (elemen, resep, dan anak sama)
+// menjadi satu node. Id diberikan secara post-order sehingga id anak selalu lebih kecil.
+func BuildRecipeDag(trees []ElementNode) RecipeDag {
+	dag := RecipeDag{Nodes: []DagNode{}, Roots: make([]int, len(trees))}
+	byPointer := make(map[*ElementNode]int)
+	byShape := make(map[string]int)
+
+	var visit func(node *ElementNode) int
+	visit = func(node *ElementNode) int {
+		if id, ok := byPointer[node]; ok {
+			return id
+		}
+
+		children := make([]int, len(node.Children))
+		for i, child := range node.Children {
+			children[i] = visit(child)
+		}
+
+		var key strings.Builder
+		key.WriteString(node.Result)
+		for _, src := range node.Sources {
+			key.WriteString("|" + src)
+		}
+		key.WriteString("|")
+		for _, id := range children {
+			key.WriteString("," + strconv.Itoa(id))
+		}
+
+		id, ok := byShape[key.String()]
+		if !ok {
+			id = len(dag.Nodes)
+			dag.Nodes = append(dag.Nodes, DagNode{
+				ID:       id,
+				Name:     node.Result,
+				Sources:  node.Sources,
+				Children: children,
+			})
+			byShape[key.String()] = id
+		}
+		byPointer[node] = id
+		return id
+	}
+
+	for i := range trees {
+		dag.Roots[i] = visit(&trees[i])
+	}
+	return dag
+}
+
+// UseDagFormat mengganti pohon pada Result dengan representasi DAG
+func UseDagFormat(result *Result) {
+	dag := BuildRecipeDag(result.RecipeTree)
+	result.Dag = &dag
+	result.RecipeTree = []ElementNode{}
+}
@@ -2,33 +2,48 @@ package cmd
 
 import (
 	"context"
-	"sync"
+	"strconv"
 	"time"
 )
 
-var (
-	baseElements     = map[string]bool{"water": true, "fire": true, "earth": true, "air": true}
-
-)
-
-
-func isBaseElement(e string) bool {
-	return baseElements[e]
+// dfsMemoKey menambahkan sisa kedalaman pada kunci memo jika batas kedalaman
+// dapat memotong pohon target. Karena bahan selalu bertier lebih rendah, tinggi
+// pohon tidak pernah melebihi tier elemennya.
+func dfsMemoKey(key string, tiers TierMap, target string, depth, maxDepth int) string {
+	if maxDepth <= 0 {
+		return key
+	}
+	remaining := maxDepth - depth
+	if remaining >= tiers[target] {
+		return key
+	}
+	return key + "@" + strconv.Itoa(remaining)
 }
 
-
+// dfsBuildTree membangun pohon secara DFS. maxDepth <= 0 berarti tanpa batas kedalaman;
+// cabang yang melewati batas tidak menghasilkan pohon.
 func dfsBuildTree(
 	recipes RecipeMap,
 	tiers TierMap,
 	target string,
+	need uint64,
 	maxPaths int,
 	visited map[string]bool,
 	depth int,
 	maxDepth int,
 	memo *MemoCache, 
+	run *searchRun,
 ) []*ElementNode {
+	run.metrics.visits.Add(1)
+
+	// Elemen wajib yang masih harus muncul di bawah target
+	need = run.req.remaining(target, need)
+	key := dfsMemoKey(run.req.cacheKey(target, need), tiers, target, depth, maxDepth)
 
-	if isBaseElement(target) {
+	if run.opts.isLeaf(target) {
+		if need != 0 {
+			return nil
+		}
 		return []*ElementNode{
 			{
 				Result:   target,
@@ -38,20 +53,22 @@ func dfsBuildTree(
 		}
 	}
 
-	memo.mu.Lock()
-	if val, ok := memo.store[target]; ok {
-		memo.mu.Unlock()
+	if val, ok := memo.get(key, maxPaths); ok {
+		run.metrics.memoHits.Add(1)
 		return val
 	}
-	memo.mu.Unlock()
 
-	if visited[target] || depth > maxDepth {
-		return []*ElementNode{{Result: target}}
+	if run.stopped() {
+		return nil
+	}
+
+	if visited[target] || (maxDepth > 0 && depth >= maxDepth) {
+		return nil
 	}
 
 	combos, exists := recipes[target]
 	if !exists || len(combos) == 0 {
-		return []*ElementNode{{Result: target}}
+		return nil
 	}
 
 	newVisited := make(map[string]bool)
@@ -60,130 +77,75 @@ func dfsBuildTree(
 	}
 	newVisited[target] = true
 
+	run.emit(EventExpand, target, depth, nil, 0)
+	run.metrics.expansions.Add(1)
 	parentTier := tiers[target]
 
-	resultsChan := make(chan *ElementNode, maxPaths)
-	_, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	comboChan := make(chan []string, len(combos))
-
-	var wg sync.WaitGroup
-	var result []*ElementNode
-	var resultMutex sync.Mutex
-
-	worker := func() {
-		defer wg.Done()
-		for combo := range comboChan {
-			// Cek limit sebelum lanjut
-			resultMutex.Lock()
-			if len(result) >= maxPaths {
-				resultMutex.Unlock()
-				return
-			}
-			resultMutex.Unlock()
-	
-			if isUnbuildable(combo[0], recipes) || isUnbuildable(combo[1], recipes) {
-				continue
-			}
-	
-			tierA := tiers[combo[0]]
-			tierB := tiers[combo[1]]
-			if tierA >= parentTier || tierB >= parentTier {
-				continue
-			}
-	
-			leftTrees := dfsBuildTree(recipes, tiers, combo[0], maxPaths, newVisited, depth+1, maxDepth, memo)
-			rightTrees := dfsBuildTree(recipes, tiers, combo[1], maxPaths, newVisited, depth+1, maxDepth, memo)
-	
-			for _, left := range leftTrees {
-				for _, right := range rightTrees {
-					resultMutex.Lock()
-					if len(result) >= maxPaths {
-						resultMutex.Unlock()
-						cancel()
-						return
-					}
-					newNode := &ElementNode{
-						Result:   target,
-						Sources:  combo,
-						Children: []*ElementNode{left, right},
-					}
-					result = append(result, newNode)
-					resultMutex.Unlock()
-				}
-			}
+	expand := func(ctx context.Context, combo []string) []*ElementNode {
+		if run.prune(recipes, tiers, parentTier, combo) {
+			return nil
 		}
-	}
-	
-
-	workerCount := 4
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go worker()
-	}
 
-	go func() {
-		for _, combo := range combos {
-			comboChan <- combo
-		}
-		close(comboChan)
-	}()
-
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	for node := range resultsChan {
-		resultMutex.Lock()
-		result = append(result, node)
-		if len(result) >= maxPaths {
-			cancel()
+		nodes := expandRequired(ctx, run, target, combo, need, maxPaths, func(element string, need uint64, limit int) []*ElementNode {
+			return dfsBuildTree(recipes, tiers, element, need, limit, newVisited, depth+1, maxDepth, memo, run)
+		})
+		if len(nodes) > 0 {
+			run.emit(EventRecipe, target, depth, combo, len(nodes))
 		}
-		resultMutex.Unlock()
+		return nodes
 	}
 
+	workerCount := 4
+	result := combineParallel(combos, workerCount, maxPaths, run, expand)
+	run.emit(EventComplete, target, depth, nil, len(result))
 
-	memo.mu.Lock()
-	memo.store[target] = result
-	memo.mu.Unlock()
+	memo.put(key, result, maxPaths)
 
 	return result
 }
 
 
-func MainDfs(recipes RecipeMap, tiers TierMap, targetElement string, maxRecipes int) Result {
-
-	// atomic.StoreInt64(&visitedNodeCount, 0)
+// MainDfs menjalankan DFS dengan batas tinggi pohon maxDepth, 0 berarti tanpa batas
+func MainDfs(ctx context.Context, recipes RecipeMap, tiers TierMap, targetElement string, maxRecipes int, maxDepth int, opts *SearchOptions) Result {
 
 	startTime := time.Now()
-	cache := &MemoCache{store: make(map[string][]*ElementNode)}
+	cache := newMemoCache()
+	run := newSearchRun(ctx, "dfs", recipes, tiers, opts)
+	defer run.cancel()
 
-	if isBaseElement(targetElement) {
-		return Result{
+	if run.opts.isLeaf(targetElement) {
+		result := Result{
 			TargetElement: targetElement,
 			RecipeTree:    []ElementNode{{Result: targetElement}},
-			VisitedNodes:  1,
 			SearchTime:    0,
 		}
+		run.metrics.visits.Add(1)
+		run.finish(&result)
+		return result
 	}
 	
-	trees := dfsBuildTree(recipes, tiers, targetElement, maxRecipes, make(map[string]bool), 0, 15, cache)
+	trees := dfsBuildTree(recipes, tiers, targetElement, run.req.all, maxRecipes, make(map[string]bool), 0, maxDepth, cache, run)
 
 	searchTime := float64(time.Since(startTime).Microseconds())
 	
-    totalNodes := 0
-    for _, tree := range trees {
-        totalNodes += countNodes(tree)
-    }
 	result := Result{
 		TargetElement: targetElement,
 		RecipeTree:    flattenTreeList(trees),
-		VisitedNodes:  totalNodes,
 		SearchTime:    searchTime,
+		DepthLimit:    maxDepth,
 	}
+	run.finish(&result)
 	
 	return result
 }
 
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "dfs",
+		Description: "Depth-first search over recipe combinations, returns up to MaxRecipe trees",
+		MultiTree:   true,
+		Parameters:  []AlgorithmParam{paramMaxRecipe, paramDepthLimit, paramInventory, paramExclude, paramRequire},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainDfs(ctx, recipes, tiers, req.Target, req.MaxRecipe, req.DepthLimit, req.Options)
+	}))
+}
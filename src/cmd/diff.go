@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Elemen yang ditambahkan atau dihapus beserta tiernya
+type DiffElement struct {
+	Name string `json:"name"`
+	Tier int    `json:"tier"`
+}
+
+// Perubahan resep satu elemen yang ada di kedua dataset
+type RecipeChange struct {
+	Element string     `json:"element"`
+	Added   [][]string `json:"added"`
+	Removed [][]string `json:"removed"`
+}
+
+// Perpindahan tier satu elemen
+type TierChange struct {
+	Element string `json:"element"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+// Perbedaan antara dua dataset, dari dataset lama (From) ke dataset baru (To)
+type DatasetDiff struct {
+	FromVersion     string         `json:"fromVersion"`
+	ToVersion       string         `json:"toVersion"`
+	AddedElements   []DiffElement  `json:"addedElements"`
+	RemovedElements []DiffElement  `json:"removedElements"`
+	RecipeChanges   []RecipeChange `json:"recipeChanges"`
+	TierChanges     []TierChange   `json:"tierChanges"`
+}
+
+// Empty bernilai true jika kedua dataset sama
+func (d DatasetDiff) Empty() bool {
+	return len(d.AddedElements) == 0 && len(d.RemovedElements) == 0 &&
+		len(d.RecipeChanges) == 0 && len(d.TierChanges) == 0
+}
+
+// recipePairs mengelompokkan resep berdasarkan pasangan bahan tanpa memperhatikan
+// urutan, sehingga a+b dan b+a dianggap resep yang sama
+func recipePairs(combos [][]string) (map[string][]string, []string) {
+	pairs := make(map[string][]string, len(combos))
+	var keys []string
+	for _, combo := range combos {
+		if len(combo) != 2 {
+			continue
+		}
+		a, b := combo[0], combo[1]
+		if a > b {
+			a, b = b, a
+		}
+		key := a + "+" + b
+		if _, ok := pairs[key]; !ok {
+			pairs[key] = []string{a, b}
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return pairs, keys
+}
+
+// DiffDatasets membandingkan dataset lama dengan dataset baru
+func DiffDatasets(oldRecipes RecipeMap, oldTiers TierMap, newRecipes RecipeMap, newTiers TierMap) DatasetDiff {
+	diff := DatasetDiff{
+		FromVersion:     DatasetVersion(oldRecipes, oldTiers),
+		ToVersion:       DatasetVersion(newRecipes, newTiers),
+		AddedElements:   []DiffElement{},
+		RemovedElements: []DiffElement{},
+		RecipeChanges:   []RecipeChange{},
+		TierChanges:     []TierChange{},
+	}
+
+	for _, name := range elementsByTier(newRecipes, newTiers) {
+		if _, ok := oldRecipes[name]; !ok {
+			diff.AddedElements = append(diff.AddedElements, DiffElement{Name: name, Tier: newTiers[name]})
+		}
+	}
+
+	for _, name := range elementsByTier(oldRecipes, oldTiers) {
+		combos, ok := newRecipes[name]
+		if !ok {
+			diff.RemovedElements = append(diff.RemovedElements, DiffElement{Name: name, Tier: oldTiers[name]})
+			continue
+		}
+
+		if oldTiers[name] != newTiers[name] {
+			diff.TierChanges = append(diff.TierChanges, TierChange{Element: name, From: oldTiers[name], To: newTiers[name]})
+		}
+
+		oldPairs, oldKeys := recipePairs(oldRecipes[name])
+		newPairs, newKeys := recipePairs(combos)
+		change := RecipeChange{Element: name, Added: [][]string{}, Removed: [][]string{}}
+		for _, key := range newKeys {
+			if _, ok := oldPairs[key]; !ok {
+				change.Added = append(change.Added, newPairs[key])
+			}
+		}
+		for _, key := range oldKeys {
+			if _, ok := newPairs[key]; !ok {
+				change.Removed = append(change.Removed, oldPairs[key])
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			diff.RecipeChanges = append(diff.RecipeChanges, change)
+		}
+	}
+
+	return diff
+}
+
+// Text menampilkan perbedaan dalam format yang mudah dibaca
+func (d DatasetDiff) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dataset %s → %s\n", d.FromVersion, d.ToVersion)
+	if d.Empty() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d added, %d removed, %d with recipe changes, %d tier changes\n",
+		len(d.AddedElements), len(d.RemovedElements), len(d.RecipeChanges), len(d.TierChanges))
+
+	if len(d.AddedElements) > 0 {
+		b.WriteString("\nAdded elements:\n")
+		for _, el := range d.AddedElements {
+			fmt.Fprintf(&b, "  + %s (tier %d)\n", el.Name, el.Tier)
+		}
+	}
+	if len(d.RemovedElements) > 0 {
+		b.WriteString("\nRemoved elements:\n")
+		for _, el := range d.RemovedElements {
+			fmt.Fprintf(&b, "  - %s (tier %d)\n", el.Name, el.Tier)
+		}
+	}
+	if len(d.TierChanges) > 0 {
+		b.WriteString("\nTier changes:\n")
+		for _, change := range d.TierChanges {
+			fmt.Fprintf(&b, "  ~ %s: %d → %d\n", change.Element, change.From, change.To)
+		}
+	}
+	if len(d.RecipeChanges) > 0 {
+		b.WriteString("\nRecipe changes:\n")
+		for _, change := range d.RecipeChanges {
+			fmt.Fprintf(&b, "  %s\n", change.Element)
+			for _, pair := range change.Added {
+				fmt.Fprintf(&b, "    + %s + %s\n", pair[0], pair[1])
+			}
+			for _, pair := range change.Removed {
+				fmt.Fprintf(&b, "    - %s + %s\n", pair[0], pair[1])
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import "time"
+
+// Jenis event progres pencarian
+const (
+	// Sebuah elemen mulai diekspansi (bukan dari memo)
+	EventExpand = "expand"
+	// Sebuah kombinasi bahan menghasilkan setidaknya satu pohon
+	EventRecipe = "recipe"
+	// Semua pohon untuk sebuah elemen selesai dibangun
+	EventComplete = "complete"
+	// Iterative deepening memulai iterasi dengan batas kedalaman baru
+	EventDeepen = "deepen"
+	// Salah satu sisi pencarian dua arah selesai mengekspansi satu lapisan
+	EventLayer = "layer"
+)
+
+// Sisi pencarian dua arah pada event layer
+const (
+	SideForward  = "forward"
+	SideBackward = "backward"
+)
+
+// Event progres pencarian untuk animasi di frontend
+type SearchEvent struct {
+	Type      string    `json:"type"`
+	Algorithm string    `json:"algorithm"`
+	Element   string    `json:"element"`
+	Depth     int       `json:"depth"`
+	Sources   []string  `json:"sources,omitempty"`
+	Trees     int       `json:"trees,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Waktu sejak pencarian dimulai dalam mikrodetik, sama seperti SearchTime
+	Elapsed float64 `json:"elapsed"`
+	// Hanya untuk event layer: sisi, nomor lapisan, elemen yang diekspansi pada
+	// lapisan tersebut dan titik temu baru dengan sisi lainnya
+	Side     string   `json:"side,omitempty"`
+	Layer    int      `json:"layer,omitempty"`
+	Elements []string `json:"elements,omitempty"`
+	Meeting  []string `json:"meeting,omitempty"`
+}
+
+// emit mengirim event jika pemanggil meminta event progres
+func (r *searchRun) emit(eventType, element string, depth int, sources []string, trees int) {
+	if r.opts.OnEvent == nil {
+		return
+	}
+	r.send(SearchEvent{
+		Type:    eventType,
+		Element: element,
+		Depth:   depth,
+		Sources: sources,
+		Trees:   trees,
+	})
+}
+
+// emitLayer mengirim event layer untuk satu sisi pencarian dua arah
+func (r *searchRun) emitLayer(side string, layer int, target string, elements, meeting []string) {
+	if r.opts.OnEvent == nil {
+		return
+	}
+	r.send(SearchEvent{
+		Type:     EventLayer,
+		Element:  target,
+		Depth:    layer,
+		Side:     side,
+		Layer:    layer,
+		Elements: elements,
+		Meeting:  meeting,
+	})
+}
+
+// send melengkapi algoritma dan waktu event lalu meneruskannya ke OnEvent
+func (r *searchRun) send(ev SearchEvent) {
+	now := time.Now()
+	ev.Algorithm = r.algorithm
+	ev.Timestamp = now
+	ev.Elapsed = float64(now.Sub(r.start).Microseconds())
+	r.opts.OnEvent(ev)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// MainIddfs menjalankan DFS dengan batas kedalaman yang dinaikkan satu per satu
+// sampai maxRecipes pohon lengkap ditemukan atau batas maxDepth tercapai
+// (0 berarti sampai tier target, karena tinggi pohon tidak melebihi tier).
+// Pohon yang lebih dangkal selalu dikembalikan lebih dulu.
+func MainIddfs(ctx context.Context, recipes RecipeMap, tiers TierMap, target string, maxRecipes int, maxDepth int, opts *SearchOptions) Result {
+	startTime := time.Now()
+	run := newSearchRun(ctx, "iddfs", recipes, tiers, opts)
+	result := Result{TargetElement: target, RecipeTree: []ElementNode{}}
+
+	if run.opts.isLeaf(target) {
+		result.RecipeTree = []ElementNode{{Result: target}}
+		run.metrics.visits.Add(1)
+		run.finish(&result)
+		return result
+	}
+
+	limit := tiers[target]
+	if maxDepth > 0 && maxDepth < limit {
+		limit = maxDepth
+	}
+
+	// Memo dipakai bersama antar iterasi, kuncinya sudah memuat sisa kedalaman
+	cache := newMemoCache()
+	var found []*ElementNode
+	seen := make(map[string]bool)
+
+	for depth := 1; depth <= limit && len(found) < maxRecipes && !run.stopped(); depth++ {
+		run.emit(EventDeepen, target, depth, nil, len(found))
+		result.DepthLimit = depth
+		trees := dfsBuildTree(recipes, tiers, target, run.req.all, maxRecipes, make(map[string]bool), 0, depth, cache, run)
+		for _, tree := range trees {
+			if len(found) >= maxRecipes {
+				break
+			}
+			// Pohon dari iterasi sebelumnya ditemukan lagi pada iterasi berikutnya
+			sig := treeSignature(tree, false)
+			if !seen[sig] {
+				seen[sig] = true
+				found = append(found, tree)
+			}
+		}
+	}
+
+	result.RecipeTree = flattenTreeList(found)
+	result.SearchTime = float64(time.Since(startTime).Microseconds())
+	run.finish(&result)
+	return result
+}
+
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "iddfs",
+		Description: "Iterative-deepening DFS, raises the depth bound until MaxRecipe complete trees are found; shallower trees come first",
+		MultiTree:   true,
+		Parameters:  []AlgorithmParam{paramMaxRecipe, paramDepthLimit, paramInventory, paramExclude, paramRequire},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainIddfs(ctx, recipes, tiers, req.Target, req.MaxRecipe, req.DepthLimit, req.Options)
+	}))
+}
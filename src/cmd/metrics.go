@@ -0,0 +1,60 @@
+package cmd
+
+import "sync/atomic"
+
+// SearchMetrics mencatat instrumentasi pencarian dengan counter atomik sehingga
+// aman dipakai oleh banyak worker sekaligus dan dapat dibandingkan antar algoritma
+type SearchMetrics struct {
+	// Setiap kali pencarian mengunjungi sebuah elemen, termasuk daun dan hit memo
+	visits atomic.Int64
+	// Kunjungan yang benar-benar mengekspansi resep elemen (bukan dari memo)
+	expansions atomic.Int64
+	memoHits   atomic.Int64
+	// Kombinasi yang dilewati karena aturan tier
+	prunedTier atomic.Int64
+	// Kombinasi yang dilewati karena bahannya tidak dapat dibuat atau dilarang
+	prunedUnbuildable atomic.Int64
+	// Kombinasi yang dilewati karena tidak mungkin memuat elemen wajib
+	prunedConstraint atomic.Int64
+	// Jumlah ElementNode yang dibuat
+	nodesBuilt atomic.Int64
+}
+
+// Ringkasan metrik pada Result
+type MetricsReport struct {
+	Visits            int64 `json:"visits"`
+	Expansions        int64 `json:"expansions"`
+	MemoHits          int64 `json:"memoHits"`
+	PrunedTier        int64 `json:"prunedTier"`
+	PrunedUnbuildable int64 `json:"prunedUnbuildable"`
+	PrunedConstraint  int64 `json:"prunedConstraint"`
+	NodesBuilt        int64 `json:"nodesBuilt"`
+	TreesEmitted      int64 `json:"treesEmitted"`
+}
+
+func (m *SearchMetrics) report(trees int) MetricsReport {
+	return MetricsReport{
+		Visits:            m.visits.Load(),
+		Expansions:        m.expansions.Load(),
+		MemoHits:          m.memoHits.Load(),
+		PrunedTier:        m.prunedTier.Load(),
+		PrunedUnbuildable: m.prunedUnbuildable.Load(),
+		PrunedConstraint:  m.prunedConstraint.Load(),
+		NodesBuilt:        m.nodesBuilt.Load(),
+		TreesEmitted:      int64(trees),
+	}
+}
+
+// prune memeriksa aturan tier dan bahan yang tidak dapat dibuat untuk satu kombinasi,
+// mengembalikan true jika kombinasi harus dilewati
+func (r *searchRun) prune(recipes RecipeMap, tiers TierMap, parentTier int, pair []string) bool {
+	if r.opts.isUnbuildable(pair[0], recipes) || r.opts.isUnbuildable(pair[1], recipes) {
+		r.metrics.prunedUnbuildable.Add(1)
+		return true
+	}
+	if tiers[pair[0]] >= parentTier || tiers[pair[1]] >= parentTier {
+		r.metrics.prunedTier.Add(1)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Awalan yang diabaikan saat mencocokkan nama, misalnya "the sun" -> "sun"
+var ignoredArticles = []string{"the ", "a ", "an "}
+
+// NameResolver memetakan nama masukan pengguna ke nama elemen kanonik
+type NameResolver struct {
+	exact   map[string]string
+	compact map[string]string
+	aliases map[string]string
+}
+
+// NormalizeName menyeragamkan huruf, spasi, dan tanda baca pada nama elemen
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// compactName menghapus seluruh spasi sehingga "t rex" dan "trex" dianggap sama
+func compactName(name string) string {
+	return strings.ReplaceAll(NormalizeName(name), " ", "")
+}
+
+// NewNameResolver membangun indeks nama dari RecipeMap dan tabel alias (alias -> nama kanonik).
+// Alias yang menunjuk ke elemen yang tidak ada akan diabaikan.
+func NewNameResolver(recipes RecipeMap, aliases map[string]string) *NameResolver {
+	r := &NameResolver{
+		exact:   make(map[string]string, len(recipes)),
+		compact: make(map[string]string, len(recipes)),
+		aliases: make(map[string]string, len(aliases)),
+	}
+
+	for element := range recipes {
+		r.index(r.exact, NormalizeName(element), element)
+		r.index(r.compact, compactName(element), element)
+	}
+
+	for alias, canonical := range aliases {
+		target, ok := r.lookup(canonical)
+		if !ok {
+			continue
+		}
+		r.aliases[NormalizeName(alias)] = target
+	}
+
+	return r
+}
+
+// index menyimpan key -> element, jika bentrok dipilih nama terkecil agar hasil stabil
+func (r *NameResolver) index(m map[string]string, key, element string) {
+	if existing, ok := m[key]; ok && existing < element {
+		return
+	}
+	m[key] = element
+}
+
+func (r *NameResolver) lookup(name string) (string, bool) {
+	key := NormalizeName(name)
+	if key == "" {
+		return "", false
+	}
+	if element, ok := r.exact[key]; ok {
+		return element, true
+	}
+	if element, ok := r.aliases[key]; ok {
+		return element, true
+	}
+	if element, ok := r.compact[strings.ReplaceAll(key, " ", "")]; ok {
+		return element, true
+	}
+	return "", false
+}
+
+// Resolve mengembalikan nama kanonik untuk name, ok bernilai false jika tidak ditemukan
+func (r *NameResolver) Resolve(name string) (string, bool) {
+	if element, ok := r.lookup(name); ok {
+		return element, true
+	}
+
+	key := NormalizeName(name)
+	for _, article := range ignoredArticles {
+		if strings.HasPrefix(key, article) {
+			if element, ok := r.lookup(strings.TrimPrefix(key, article)); ok {
+				return element, true
+			}
+		}
+	}
+
+	// Bentuk jamak sederhana: humans -> human, boxes -> box, berries -> berry
+	for _, singular := range singularForms(key) {
+		if element, ok := r.lookup(singular); ok {
+			return element, true
+		}
+	}
+
+	return "", false
+}
+
+func singularForms(key string) []string {
+	var forms []string
+	if strings.HasSuffix(key, "ies") {
+		forms = append(forms, strings.TrimSuffix(key, "ies")+"y")
+	}
+	if strings.HasSuffix(key, "es") {
+		forms = append(forms, strings.TrimSuffix(key, "es"))
+	}
+	if strings.HasSuffix(key, "s") {
+		forms = append(forms, strings.TrimSuffix(key, "s"))
+	}
+	return forms
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+)
+
+// comboExpander membangun pohon untuk satu kombinasi bahan
+type comboExpander func(ctx context.Context, pair []string) []*ElementNode
+
+// combineParallel menjalankan expand untuk setiap kombinasi dengan beberapa worker
+// dan mengumpulkan paling banyak maxPaths pohon.
+//
+// Pada mode non-deterministic pohon diambil sesuai urutan selesai. Pada mode
+// deterministic hasil setiap kombinasi disimpan per indeks lalu digabung sesuai
+// urutan di RecipeMap; worker berhenti lebih awal begitu prefiks kombinasi yang
+// sudah selesai mencukupi maxPaths, sehingga hasilnya sama dengan eksekusi sekuensial.
+func combineParallel(
+	combos [][]string,
+	workerCount int,
+	maxPaths int,
+	run *searchRun,
+	expand comboExpander,
+) []*ElementNode {
+	if run.opts.Deterministic {
+		return combineOrdered(combos, workerCount, maxPaths, run, expand)
+	}
+
+	var result []*ElementNode
+	queue := make(chan []string, len(combos))
+	resultsChan := make(chan *ElementNode, workerCount)
+	ctx, cancel := context.WithCancel(run.ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	worker := func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case pair, ok := <-queue:
+				if !ok {
+					return
+				}
+				for _, node := range expand(ctx, pair) {
+					select {
+					case resultsChan <- node:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	go func() {
+		for _, pair := range combos {
+			queue <- pair
+		}
+		close(queue)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	for node := range resultsChan {
+		result = append(result, node)
+		if len(result) >= maxPaths {
+			cancel()
+			break
+		}
+	}
+
+	return result
+}
+
+func combineOrdered(
+	combos [][]string,
+	workerCount int,
+	maxPaths int,
+	run *searchRun,
+	expand comboExpander,
+) []*ElementNode {
+	buckets := make([][]*ElementNode, len(combos))
+	finished := make([]bool, len(combos))
+	ctx, cancel := context.WithCancel(run.ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	prefixEnd, prefixCount := 0, 0
+	queue := make(chan int, len(combos))
+	for i := range combos {
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	worker := func() {
+		defer wg.Done()
+		for idx := range queue {
+			if ctx.Err() != nil {
+				return
+			}
+			trees := expand(ctx, combos[idx])
+
+			mu.Lock()
+			buckets[idx] = trees
+			finished[idx] = true
+			for prefixEnd < len(combos) && finished[prefixEnd] {
+				prefixCount += len(buckets[prefixEnd])
+				prefixEnd++
+			}
+			if prefixCount >= maxPaths {
+				cancel()
+			}
+			mu.Unlock()
+		}
+	}
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go worker()
+	}
+	wg.Wait()
+
+	// Hanya prefiks yang sudah lengkap yang dipakai, kombinasi setelahnya
+	// mungkin terpotong oleh cancel. Jika pencarian sudah dihentikan, semua
+	// hasil parsial dipakai.
+	end := prefixEnd
+	if run.stopped() {
+		end = len(combos)
+	}
+	var result []*ElementNode
+	for i := 0; i < end && len(result) < maxPaths; i++ {
+		for _, node := range buckets[i] {
+			if len(result) >= maxPaths {
+				break
+			}
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// treeBuilder membangun paling banyak limit pohon untuk elemen dengan kebutuhan need
+type treeBuilder func(element string, need uint64, limit int) []*ElementNode
+
+// buildPair membangun pohon kedua bahan secukupnya untuk maxPaths pohon target.
+// expandPair memasangkan pohon per lapisan max(i, j), sehingga awalnya cukup
+// ceil(sqrt(maxPaths)) pohon per bahan. Jika salah satu bahan memiliki lebih sedikit
+// pohon, bahan lainnya dibangun ulang dengan batas ceil(maxPaths/n).
+func buildPair(pair []string, leftNeed, rightNeed uint64, maxPaths int, build treeBuilder) (left, right []*ElementNode) {
+	limit := 1
+	for limit*limit < maxPaths {
+		limit++
+	}
+	right = build(pair[1], rightNeed, limit)
+	if len(right) == 0 {
+		return nil, nil
+	}
+	left = build(pair[0], leftNeed, limit)
+	if len(left) == 0 {
+		return nil, nil
+	}
+
+	switch {
+	case len(left)*len(right) >= maxPaths:
+	case len(right) < limit && len(left) == limit:
+		left = build(pair[0], leftNeed, (maxPaths+len(right)-1)/len(right))
+	case len(left) < limit && len(right) == limit:
+		right = build(pair[1], rightNeed, (maxPaths+len(left)-1)/len(left))
+	}
+	return left, right
+}
+
+// expandPair menggabungkan pasangan pohon kiri dan kanan menjadi pohon target,
+// paling banyak maxPaths pohon. Pasangan (i, j) diurutkan menurut max(i, j) lalu i
+// lalu j, sehingga maxPaths pasangan pertama hanya memakai sekitar sqrt(maxPaths)
+// pohon per bahan dan urutannya tidak bergantung pada panjang daftar yang dibangun.
+// Setiap node baru dicatat pada anggaran node pencarian dan penggabungan berhenti
+// begitu pencarian dihentikan atau anggaran habis.
+func expandPair(ctx context.Context, run *searchRun, target string, pair []string, leftTrees, rightTrees []*ElementNode, maxPaths int) []*ElementNode {
+	var nodes []*ElementNode
+	add := func(i, j int) bool {
+		if len(nodes) >= maxPaths || ctx.Err() != nil || !run.spend(1) {
+			return false
+		}
+		nodes = append(nodes, &ElementNode{
+			Result:   target,
+			Sources:  pair,
+			Children: []*ElementNode{leftTrees[i], rightTrees[j]},
+		})
+		return true
+	}
+
+	shells := max(len(leftTrees), len(rightTrees))
+	if len(leftTrees) == 0 || len(rightTrees) == 0 {
+		shells = 0
+	}
+	for k := 0; k < shells; k++ {
+		// Lapisan k: (0,k) ... (k-1,k) lalu (k,0) ... (k,k)
+		if k < len(rightTrees) {
+			for i := 0; i < k && i < len(leftTrees); i++ {
+				if !add(i, k) {
+					return nodes
+				}
+			}
+		}
+		if k < len(leftTrees) {
+			for j := 0; j <= k && j < len(rightTrees); j++ {
+				if !add(k, j) {
+					return nodes
+				}
+			}
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import "fmt"
+
+// Satu langkah penggabungan pada rencana crafting
+type CraftStep struct {
+	Step        int      `json:"step"`
+	Result      string   `json:"result"`
+	Ingredients []string `json:"ingredients"`
+	Text        string   `json:"text"`
+}
+
+// Rencana crafting linear dari sebuah pohon resep
+type CraftPlan struct {
+	Steps      []CraftStep `json:"steps"`
+	TotalSteps int         `json:"totalSteps"`
+}
+
+// BuildCraftPlan mengubah pohon resep menjadi daftar langkah berurutan tanpa duplikasi.
+// Setiap langkah hanya memakai elemen daun atau hasil langkah sebelumnya.
+func BuildCraftPlan(tree *ElementNode) CraftPlan {
+	plan := CraftPlan{Steps: []CraftStep{}}
+	crafted := make(map[string]bool)
+
+	var walk func(node *ElementNode)
+	walk = func(node *ElementNode) {
+		if node == nil || len(node.Children) == 0 || crafted[node.Result] {
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+		// Elemen yang sama bisa muncul lagi di dalam anaknya sendiri jika datanya tidak konsisten
+		if crafted[node.Result] {
+			return
+		}
+		crafted[node.Result] = true
+
+		step := CraftStep{
+			Step:        len(plan.Steps) + 1,
+			Result:      node.Result,
+			Ingredients: node.Sources,
+		}
+		if len(node.Sources) == 2 {
+			step.Text = fmt.Sprintf("%d. %s + %s → %s", step.Step, node.Sources[0], node.Sources[1], node.Result)
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	walk(tree)
+
+	plan.TotalSteps = len(plan.Steps)
+	return plan
+}
+
+// UsePlanFormat mengganti pohon pada Result dengan rencana crafting linear
+func UsePlanFormat(result *Result) {
+	result.Plans = make([]CraftPlan, len(result.RecipeTree))
+	for i := range result.RecipeTree {
+		result.Plans[i] = BuildCraftPlan(&result.RecipeTree[i])
+	}
+	result.RecipeTree = []ElementNode{}
+}
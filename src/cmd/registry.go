@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Nama parameter request yang dapat didukung sebuah algoritma
+const (
+	ParamMaxRecipe  = "MaxRecipe"
+	ParamCostMode   = "CostMode"
+	ParamDepthLimit = "DepthLimit"
+	ParamInventory  = "Inventory"
+	ParamExclude    = "Exclude"
+	ParamRequire    = "Require"
+)
+
+// Deskripsi satu parameter yang didukung algoritma
+type AlgorithmParam struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Values      []string `json:"values,omitempty"`
+	Description string   `json:"description"`
+}
+
+// Informasi algoritma yang terdaftar
+type AlgorithmInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// MultiTree bernilai true jika algoritma mengembalikan banyak pohon sehingga
+	// hasilnya dapat dibandingkan dengan algoritma lain
+	MultiTree  bool             `json:"multiTree"`
+	Parameters []AlgorithmParam `json:"parameters"`
+}
+
+// Param mengembalikan parameter dengan nama tertentu jika didukung
+func (info AlgorithmInfo) Param(name string) (AlgorithmParam, bool) {
+	for _, p := range info.Parameters {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return AlgorithmParam{}, false
+}
+
+// Parameter pencarian yang diteruskan ke Searcher
+type SearchRequest struct {
+	Target    string
+	MaxRecipe int
+	CostMode  string
+	// Batas tinggi pohon, 0 berarti tanpa batas
+	DepthLimit int
+	Options    *SearchOptions
+	// Indeks balik dataset, boleh nil
+	Index ReverseIndex
+}
+
+// Searcher adalah algoritma pencarian yang dapat dipanggil melalui registry
+type Searcher interface {
+	Info() AlgorithmInfo
+	Search(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result
+}
+
+// SearchFunc mengubah fungsi biasa menjadi Searcher
+type SearchFunc func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result
+
+type funcSearcher struct {
+	info   AlgorithmInfo
+	search SearchFunc
+}
+
+func (s funcSearcher) Info() AlgorithmInfo { return s.info }
+
+func (s funcSearcher) Search(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+	return s.search(ctx, recipes, tiers, req)
+}
+
+// NewSearcher membuat Searcher dari informasi algoritma dan fungsi pencariannya
+func NewSearcher(info AlgorithmInfo, search SearchFunc) Searcher {
+	return funcSearcher{info: info, search: search}
+}
+
+var registry = struct {
+	mu    sync.RWMutex
+	byKey map[string]Searcher
+	order []string
+}{byKey: make(map[string]Searcher)}
+
+// Register mendaftarkan algoritma, panic jika namanya sudah dipakai
+func Register(s Searcher) {
+	name := s.Info().Name
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.byKey[name]; ok {
+		panic(fmt.Sprintf("cmd: algorithm %q registered twice", name))
+	}
+	registry.byKey[name] = s
+	registry.order = append(registry.order, name)
+}
+
+// Lookup mencari algoritma berdasarkan nama
+func Lookup(name string) (Searcher, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	s, ok := registry.byKey[name]
+	return s, ok
+}
+
+// Algorithms mengembalikan semua algoritma terdaftar sesuai urutan pendaftaran
+func Algorithms() []AlgorithmInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	infos := make([]AlgorithmInfo, len(registry.order))
+	for i, name := range registry.order {
+		infos[i] = registry.byKey[name].Info()
+	}
+	return infos
+}
+
+// Parameter yang dipakai bersama oleh beberapa algoritma
+var (
+	paramMaxRecipe = AlgorithmParam{
+		Name: ParamMaxRecipe, Type: "integer", Required: true,
+		Description: fmt.Sprintf("Maximum number of recipe trees to return, at most %d", MaxRecipeLimit),
+	}
+	paramDepthLimit = AlgorithmParam{
+		Name: ParamDepthLimit, Type: "integer",
+		Description: "Maximum tree height in combine steps, 0 for no limit",
+	}
+	paramInventory = AlgorithmParam{
+		Name: ParamInventory, Type: "string[]",
+		Description: "Elements already owned, treated as leaves",
+	}
+	paramExclude = AlgorithmParam{
+		Name: ParamExclude, Type: "string[]",
+		Description: "Elements that must not appear in any tree",
+	}
+	paramRequire = AlgorithmParam{
+		Name: ParamRequire, Type: "string[]",
+		Description: "Elements that must appear in every tree",
+	}
+)
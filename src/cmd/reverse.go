@@ -0,0 +1,31 @@
+package cmd
+
+// Satu resep yang memakai sebuah bahan: bahan digabung dengan Partner menghasilkan
+// Product. Index adalah posisi resep pada recipes[Product].
+type ReverseEdge struct {
+	Product string
+	Partner string
+	Index   int
+}
+
+// ReverseIndex memetakan setiap bahan ke resep yang memakainya. Dibangun sekali saat
+// dataset dimuat lalu hanya dibaca, sehingga aman dipakai bersama oleh banyak request.
+type ReverseIndex map[string][]ReverseEdge
+
+// BuildReverseIndex membangun indeks balik, entri per bahan diurutkan berdasarkan
+// tier dan nama produk lalu urutan resepnya
+func BuildReverseIndex(recipes RecipeMap, tiers TierMap) ReverseIndex {
+	index := make(ReverseIndex)
+	for _, product := range elementsByTier(recipes, tiers) {
+		for i, combo := range recipes[product] {
+			if len(combo) != 2 {
+				continue
+			}
+			index[combo[0]] = append(index[combo[0]], ReverseEdge{Product: product, Partner: combo[1], Index: i})
+			if combo[1] != combo[0] {
+				index[combo[1]] = append(index[combo[1]], ReverseEdge{Product: product, Partner: combo[0], Index: i})
+			}
+		}
+	}
+	return index
+}
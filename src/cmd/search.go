@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Alasan pencarian dihentikan sebelum selesai
+const (
+	TruncatedTimeout    = "timeout"
+	TruncatedCanceled   = "canceled"
+	TruncatedNodeBudget = "node_budget"
+)
+
+// Batas atas MaxRecipe pada satu request
+const MaxRecipeLimit = 10000
+
+// Opsi yang berlaku untuk satu pencarian
+type SearchOptions struct {
+	// Jika true, himpunan dan urutan pohon yang dikembalikan hanya bergantung
+	// pada dataset dan request, bukan pada urutan selesainya goroutine
+	Deterministic bool
+	// Batas jumlah node yang boleh dibangun, 0 berarti tanpa batas
+	MaxNodes int64
+	// Elemen yang sudah dimiliki pemain, diperlakukan sebagai daun seperti elemen dasar
+	Inventory map[string]bool
+	// Elemen yang tidak boleh muncul di pohon resep
+	Exclude map[string]bool
+	// Elemen yang wajib muncul di setiap pohon resep yang dikembalikan
+	Require []string
+	// Dipanggil untuk setiap event progres pencarian, boleh dipanggil dari banyak goroutine
+	OnEvent func(SearchEvent)
+}
+
+// isLeaf bernilai true untuk elemen dasar dan elemen yang sudah dimiliki
+func (o *SearchOptions) isLeaf(e string) bool {
+	return isBase(e) || o.Inventory[e]
+}
+
+// isUnbuildable bernilai true untuk elemen terlarang dan elemen bukan daun yang tidak punya resep
+func (o *SearchOptions) isUnbuildable(e string, recipes RecipeMap) bool {
+	return o.Exclude[e] || (!o.isLeaf(e) && len(recipes[e]) == 0)
+}
+
+// DefaultSearchOptions mengembalikan opsi bawaan (deterministic aktif)
+func DefaultSearchOptions() *SearchOptions {
+	return &SearchOptions{Deterministic: true}
+}
+
+// searchRun menyimpan state runtime satu pencarian: context pembatalan dan
+// anggaran node yang dipakai bersama oleh semua worker
+type searchRun struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	algorithm string
+	start     time.Time
+	opts      *SearchOptions
+	req       *requirements
+	metrics   SearchMetrics
+	overspent atomic.Bool
+}
+
+func newSearchRun(ctx context.Context, algorithm string, recipes RecipeMap, tiers TierMap, opts *SearchOptions) *searchRun {
+	if opts == nil {
+		opts = DefaultSearchOptions()
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	return &searchRun{
+		ctx:       runCtx,
+		cancel:    cancel,
+		algorithm: algorithm,
+		start:     time.Now(),
+		opts:      opts,
+		req:       newRequirements(recipes, tiers, opts),
+	}
+}
+
+// stopped bernilai true jika pencarian dibatalkan atau anggaran habis.
+// Setelah berhenti, elemen baru tidak lagi diekspansi dan tidak ada node baru
+// yang dirangkai; pohon yang sudah selesai dibangun dikembalikan sebagai hasil parsial.
+func (r *searchRun) stopped() bool {
+	return r.overspent.Load() || r.ctx.Err() != nil
+}
+
+// spend mencatat n node baru pada anggaran, mengembalikan false jika anggaran habis
+func (r *searchRun) spend(n int) bool {
+	total := r.metrics.nodesBuilt.Add(int64(n))
+	if r.opts.MaxNodes > 0 && total > r.opts.MaxNodes {
+		r.overspent.Store(true)
+		return false
+	}
+	return !r.stopped()
+}
+
+// truncation mengembalikan alasan pencarian terpotong, kosong jika selesai normal
+func (r *searchRun) truncation() string {
+	if r.overspent.Load() {
+		return TruncatedNodeBudget
+	}
+	switch {
+	case errors.Is(r.ctx.Err(), context.DeadlineExceeded):
+		return TruncatedTimeout
+	case r.ctx.Err() != nil:
+		return TruncatedCanceled
+	}
+	return ""
+}
+
+// finish mengisi metrik, menandai Result jika pencarian terpotong, lalu melepas context
+func (r *searchRun) finish(result *Result) {
+	result.Metrics = r.metrics.report(len(result.RecipeTree))
+	result.VisitedNodes = int(result.Metrics.Visits)
+	if reason := r.truncation(); reason != "" {
+		result.Truncated = true
+		result.TruncatedReason = reason
+	}
+	if len(r.opts.Inventory) > 0 {
+		result.ToCraft = make([][]string, len(result.RecipeTree))
+		for i := range result.RecipeTree {
+			result.ToCraft[i] = craftList(&result.RecipeTree[i], r.opts)
+		}
+	}
+	r.cancel()
+}
+
+// craftList mengembalikan elemen yang masih harus dibuat pada sebuah pohon,
+// terurut sehingga bahan selalu muncul sebelum produknya
+func craftList(tree *ElementNode, opts *SearchOptions) []string {
+	list := []string{}
+	seen := make(map[string]bool)
+	var walk func(node *ElementNode)
+	walk = func(node *ElementNode) {
+		if node == nil || opts.isLeaf(node.Result) || seen[node.Result] {
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+		if !seen[node.Result] {
+			seen[node.Result] = true
+			list = append(list, node.Result)
+		}
+	}
+	walk(tree)
+	return list
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Cara menghitung biaya resep pada mode shortest
+const (
+	// Setiap kemunculan elemen di pohon dihitung sebagai satu langkah
+	CostModeTree = "tree"
+	// Elemen perantara yang sama hanya dihitung sekali
+	CostModeDistinct = "distinct"
+)
+
+// Resep terbaik untuk suatu elemen beserta biayanya
+type shortestEntry struct {
+	recipe []string
+	cost   int
+	// Kumpulan elemen non-dasar yang dibutuhkan, hanya dipakai pada CostModeDistinct
+	needs map[string]bool
+}
+
+// elementsByTier mengurutkan elemen berdasarkan tier lalu nama sehingga bahan
+// selalu diproses sebelum produknya
+func elementsByTier(recipes RecipeMap, tiers TierMap) []string {
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if tiers[names[i]] != tiers[names[j]] {
+			return tiers[names[i]] < tiers[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// computeShortest menghitung resep dengan jumlah langkah minimum untuk setiap elemen.
+// Pada CostModeTree hasilnya optimal karena bahan selalu bertier lebih rendah.
+// Pada CostModeDistinct masalahnya NP-hard, sehingga dipakai gabungan himpunan
+// kebutuhan bahan terbaik (hasilnya batas atas, bukan jaminan minimum).
+func computeShortest(ctx context.Context, recipes RecipeMap, tiers TierMap, mode string, opts *SearchOptions, metrics *SearchMetrics) map[string]shortestEntry {
+	best := make(map[string]shortestEntry)
+	for el := range abaseElements {
+		best[el] = shortestEntry{cost: 0, needs: map[string]bool{}}
+	}
+	for el := range opts.Inventory {
+		best[el] = shortestEntry{cost: 0, needs: map[string]bool{}}
+	}
+	for el := range opts.Exclude {
+		delete(best, el)
+	}
+
+	for _, element := range elementsByTier(recipes, tiers) {
+		if ctx.Err() != nil {
+			break
+		}
+		metrics.visits.Add(1)
+		if opts.isLeaf(element) || opts.Exclude[element] {
+			continue
+		}
+		metrics.expansions.Add(1)
+		parentTier := tiers[element]
+		found := false
+		var entry shortestEntry
+
+		for _, combo := range recipes[element] {
+			if len(combo) != 2 {
+				continue
+			}
+			left, okL := best[combo[0]]
+			right, okR := best[combo[1]]
+			if !okL || !okR {
+				metrics.prunedUnbuildable.Add(1)
+				continue
+			}
+			if tiers[combo[0]] >= parentTier || tiers[combo[1]] >= parentTier {
+				metrics.prunedTier.Add(1)
+				continue
+			}
+
+			var candidate shortestEntry
+			if mode == CostModeDistinct {
+				needs := make(map[string]bool, len(left.needs)+len(right.needs)+1)
+				for k := range left.needs {
+					needs[k] = true
+				}
+				for k := range right.needs {
+					needs[k] = true
+				}
+				needs[element] = true
+				candidate = shortestEntry{recipe: combo, cost: len(needs), needs: needs}
+			} else {
+				candidate = shortestEntry{recipe: combo, cost: left.cost + right.cost + 1}
+			}
+
+			if !found || candidate.cost < entry.cost {
+				entry = candidate
+				found = true
+			}
+		}
+
+		if found {
+			best[element] = entry
+		}
+	}
+
+	return best
+}
+
+// buildShortestTree menyusun pohon resep dari pilihan resep terbaik
+func buildShortestTree(best map[string]shortestEntry, target string, memo map[string]*ElementNode) *ElementNode {
+	if node, ok := memo[target]; ok {
+		return node
+	}
+
+	entry := best[target]
+	node := &ElementNode{Result: target}
+	if entry.recipe != nil {
+		node.Sources = entry.recipe
+		node.Children = []*ElementNode{
+			buildShortestTree(best, entry.recipe[0], memo),
+			buildShortestTree(best, entry.recipe[1], memo),
+		}
+	}
+	memo[target] = node
+	return node
+}
+
+// MainShortest mencari pohon resep dengan jumlah langkah kombinasi paling sedikit
+func MainShortest(ctx context.Context, recipes RecipeMap, tiers TierMap, target string, mode string, opts *SearchOptions) Result {
+	if mode == "" {
+		mode = CostModeTree
+	}
+
+	result := Result{TargetElement: target, RecipeTree: []ElementNode{}}
+	run := newSearchRun(ctx, "shortest", recipes, tiers, opts)
+	startTime := time.Now()
+
+	best := computeShortest(run.ctx, recipes, tiers, mode, run.opts, &run.metrics)
+	entry, ok := best[target]
+	if ok {
+		built := make(map[string]*ElementNode)
+		tree := buildShortestTree(best, target, built)
+		run.metrics.nodesBuilt.Add(int64(len(built)))
+		result.RecipeTree = flattenTreeList([]*ElementNode{tree})
+		cost := entry.cost
+		result.Cost = &cost
+		result.CostMode = mode
+		// Hanya CostModeTree yang dijamin optimal, lihat computeShortest
+		exact := mode == CostModeTree
+		result.CostExact = &exact
+	}
+
+	result.SearchTime = float64(time.Since(startTime).Microseconds())
+	run.finish(&result)
+	return result
+}
+
+func init() {
+	Register(NewSearcher(AlgorithmInfo{
+		Name:        "shortest",
+		Description: "Single recipe tree with the fewest combine steps and its cost",
+		Parameters: []AlgorithmParam{
+			{
+				Name: ParamCostMode, Type: "string", Values: []string{CostModeTree, CostModeDistinct},
+				Description: "tree counts every node (default), distinct counts shared intermediates once",
+			},
+			paramInventory, paramExclude,
+		},
+	}, func(ctx context.Context, recipes RecipeMap, tiers TierMap, req SearchRequest) Result {
+		return MainShortest(ctx, recipes, tiers, req.Target, req.CostMode, req.Options)
+	}))
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// SuggestElements mencari nama elemen yang paling mirip dengan name
+// untuk saran "did you mean"
+func SuggestElements(recipes RecipeMap, name string, limit int) []string {
+	query := NormalizeName(name)
+	if query == "" || limit <= 0 {
+		return nil
+	}
+
+	maxDistance := len(query) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for element := range recipes {
+		d := levenshtein(query, element)
+		if d <= maxDistance {
+			candidates = append(candidates, candidate{element, d})
+		} else if len(query) >= 3 && strings.Contains(element, query) {
+			// Nama parsial tetap disarankan, namun di belakang yang mirip
+			candidates = append(candidates, candidate{element, maxDistance + 1})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// levenshtein menghitung edit distance antara dua string
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
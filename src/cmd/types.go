@@ -4,14 +4,41 @@ type RecipeMap map[string][][]string
 type TierMap map[string]int
 
 type Result struct {
-	TargetElement string        `json:"targetElement"`
-	RecipeTree    []ElementNode `json:"tree"`
-	VisitedNodes  int           `json:"nodes"`
-	SearchTime    float64       `json:"time"`
+	TargetElement string `json:"targetElement"`
+	// Versi dataset yang dipakai pencarian ini
+	DatasetVersion string        `json:"datasetVersion,omitempty"`
+	RecipeTree     []ElementNode `json:"tree"`
+	VisitedNodes   int           `json:"nodes"`
+	SearchTime     float64       `json:"time"`
+	// Metrik instrumentasi; VisitedNodes sama dengan Metrics.Visits untuk semua algoritma
+	Metrics  MetricsReport `json:"metrics"`
+	Cost     *int          `json:"cost,omitempty"`
+	CostMode string        `json:"costMode,omitempty"`
+	// CostExact bernilai false jika Cost hanya batas atas dan belum tentu minimum
+	CostExact *bool `json:"costExact,omitempty"`
+	// Metrik per sisi dan titik temu, hanya diisi oleh pencarian dua arah
+	Bidirectional *BidirectionalReport `json:"bidirectional,omitempty"`
+	// Batas tinggi pohon yang dipakai DFS, pada iddfs batas saat pencarian berhenti
+	DepthLimit int `json:"depthLimit,omitempty"`
+	// Jumlah pohon resep berbeda dalam bentuk desimal karena dapat melebihi int64
+	TreeCount string `json:"treeCount,omitempty"`
+	// Elemen yang belum dimiliki dan harus dibuat untuk setiap pohon,
+	// hanya diisi jika request menyertakan inventory
+	ToCraft [][]string `json:"toCraft,omitempty"`
+	// Rencana crafting linear per pohon, diisi jika request meminta format plan
+	Plans []CraftPlan `json:"plans,omitempty"`
+	// Pohon dalam bentuk DAG, diisi jika request meminta format dag
+	Dag *RecipeDag `json:"dag,omitempty"`
+	// Truncated bernilai true jika pencarian dihentikan oleh batas waktu,
+	// pembatalan request, atau anggaran node sehingga hasilnya parsial
+	Truncated bool `json:"truncated"`
+	// Cached bernilai true jika hasil diambil dari cache hasil bersama
+	Cached          bool   `json:"cached"`
+	TruncatedReason string `json:"truncatedReason,omitempty"`
 }
 
 type ElementNode struct {
-	Result   string         `json:"name"`     
-	Sources  []string       `json:"sources"`  
-	Children []*ElementNode `json:"children"` 
-}
\ No newline at end of file
+	Result   string         `json:"name"`
+	Sources  []string       `json:"sources"`
+	Children []*ElementNode `json:"children"`
+}
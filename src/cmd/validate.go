@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resep yang bermasalah pada suatu elemen
+type RecipeIssue struct {
+	Element    string   `json:"element"`
+	Recipe     []string `json:"recipe"`
+	Ingredient string   `json:"ingredient,omitempty"`
+}
+
+// Bahan dengan tier yang sama atau lebih tinggi dari produknya
+type TierViolation struct {
+	Element        string   `json:"element"`
+	ElementTier    int      `json:"elementTier"`
+	Recipe         []string `json:"recipe"`
+	Ingredient     string   `json:"ingredient"`
+	IngredientTier int      `json:"ingredientTier"`
+}
+
+// Hasil pemeriksaan integritas RecipeMap dan TierMap
+type ValidationReport struct {
+	ElementCount        int             `json:"elementCount"`
+	RecipeCount         int             `json:"recipeCount"`
+	DanglingIngredients []RecipeIssue   `json:"danglingIngredients"`
+	TierViolations      []TierViolation `json:"tierViolations"`
+	UnreachableElements []string        `json:"unreachableElements"`
+	DuplicateRecipes    []RecipeIssue   `json:"duplicateRecipes"`
+	SelfReferencing     []RecipeIssue   `json:"selfReferencingRecipes"`
+	MalformedRecipes    []RecipeIssue   `json:"malformedRecipes"`
+}
+
+// Jumlah seluruh temuan pada laporan
+func (r *ValidationReport) IssueCount() int {
+	return len(r.DanglingIngredients) + len(r.TierViolations) + len(r.UnreachableElements) +
+		len(r.DuplicateRecipes) + len(r.SelfReferencing) + len(r.MalformedRecipes)
+}
+
+func (r *ValidationReport) HasIssues() bool {
+	return r.IssueCount() > 0
+}
+
+// ValidateDataset memeriksa konsistensi resep dan tier
+func ValidateDataset(recipes RecipeMap, tiers TierMap) ValidationReport {
+	report := ValidationReport{
+		DanglingIngredients: []RecipeIssue{},
+		TierViolations:      []TierViolation{},
+		UnreachableElements: []string{},
+		DuplicateRecipes:    []RecipeIssue{},
+		SelfReferencing:     []RecipeIssue{},
+		MalformedRecipes:    []RecipeIssue{},
+	}
+
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	report.ElementCount = len(names)
+
+	for _, element := range names {
+		seen := make(map[string]bool)
+		elementTier := tiers[element]
+
+		for _, combo := range recipes[element] {
+			report.RecipeCount++
+			if len(combo) != 2 {
+				report.MalformedRecipes = append(report.MalformedRecipes, RecipeIssue{Element: element, Recipe: combo})
+				continue
+			}
+
+			// Urutan bahan tidak berpengaruh, a+b sama dengan b+a
+			a, b := combo[0], combo[1]
+			if a > b {
+				a, b = b, a
+			}
+			key := a + "+" + b
+			if seen[key] {
+				report.DuplicateRecipes = append(report.DuplicateRecipes, RecipeIssue{Element: element, Recipe: combo})
+			}
+			seen[key] = true
+
+			for _, ingredient := range combo {
+				if ingredient == element {
+					report.SelfReferencing = append(report.SelfReferencing, RecipeIssue{
+						Element: element, Recipe: combo, Ingredient: ingredient,
+					})
+					continue
+				}
+				if _, ok := recipes[ingredient]; !ok {
+					report.DanglingIngredients = append(report.DanglingIngredients, RecipeIssue{
+						Element: element, Recipe: combo, Ingredient: ingredient,
+					})
+					continue
+				}
+				if tiers[ingredient] >= elementTier {
+					report.TierViolations = append(report.TierViolations, TierViolation{
+						Element:        element,
+						ElementTier:    elementTier,
+						Recipe:         combo,
+						Ingredient:     ingredient,
+						IngredientTier: tiers[ingredient],
+					})
+				}
+			}
+		}
+	}
+
+	reachable := reachableElements(recipes, tiers)
+	for _, element := range names {
+		if !reachable[element] {
+			report.UnreachableElements = append(report.UnreachableElements, element)
+		}
+	}
+
+	return report
+}
+
+// reachableElements mencari elemen yang dapat dibuat dari elemen dasar dengan aturan tier
+// yang sama seperti pada pencarian
+func reachableElements(recipes RecipeMap, tiers TierMap) map[string]bool {
+	reachable := make(map[string]bool)
+	for el := range abaseElements {
+		reachable[el] = true
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for element, combos := range recipes {
+			if reachable[element] {
+				continue
+			}
+			parentTier := tiers[element]
+			for _, combo := range combos {
+				if len(combo) != 2 {
+					continue
+				}
+				if !reachable[combo[0]] || !reachable[combo[1]] {
+					continue
+				}
+				if tiers[combo[0]] >= parentTier || tiers[combo[1]] >= parentTier {
+					continue
+				}
+				reachable[element] = true
+				changed = true
+				break
+			}
+		}
+	}
+
+	return reachable
+}
+
+// Ringkasan singkat laporan untuk log
+func (r *ValidationReport) Summary() string {
+	parts := []string{}
+	add := func(label string, n int) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", label, n))
+		}
+	}
+	add("dangling ingredients", len(r.DanglingIngredients))
+	add("tier violations", len(r.TierViolations))
+	add("unreachable elements", len(r.UnreachableElements))
+	add("duplicate recipes", len(r.DuplicateRecipes))
+	add("self-referencing recipes", len(r.SelfReferencing))
+	add("malformed recipes", len(r.MalformedRecipes))
+	if len(parts) == 0 {
+		return "no issues"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"tubes2_be_bfc/src/cmd"
+)
+
+// compareAlgorithms mengembalikan algoritma terdaftar yang membangun banyak pohon
+// sehingga hasilnya dapat dibandingkan
+func compareAlgorithms() []string {
+	var names []string
+	for _, info := range cmd.Algorithms() {
+		if info.MultiTree {
+			names = append(names, info.Name)
+		}
+	}
+	return names
+}
+
+type CompareResponse struct {
+	TargetElement string `json:"targetElement"`
+	MaxRecipe     int    `json:"maxRecipe"`
+	cmd.Comparison
+}
+
+// handleCompare: POST /api/compare menjalankan semua algoritma pada target dan
+// MaxRecipe yang sama, AlgorithmType pada body diabaikan
+func handleCompare(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "POST, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, "POST")
+		return
+	}
+
+	var data RequestData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidJSON, "", err.Error()))
+		return
+	}
+
+	ds := currentDataset()
+	algorithms := compareAlgorithms()
+	requests := make([]RequestData, len(algorithms))
+	for i, algorithm := range algorithms {
+		requests[i] = data
+		requests[i].AlgorithmType = algorithm
+		if apiErr := validateRequest(ds, &requests[i]); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+		// Format diterapkan setelah perbandingan karena pembandingan memakai pohon
+		requests[i].Format = ""
+	}
+
+	// Algoritma dijalankan bergantian agar waktu tempuhnya tidak saling mengganggu
+	results := make([]cmd.Result, len(requests))
+	for i, req := range requests {
+		ctx, cancel := context.WithTimeout(r.Context(), *searchTimeout)
+		results[i] = runSearch(ctx, ds, req, req.searchOptions())
+		cancel()
+	}
+
+	comparison := cmd.CompareResults(algorithms, results)
+	for i := range comparison.Results {
+		applyFormat(&comparison.Results[i].Result, data.Format)
+	}
+
+	writeJSON(w, http.StatusOK, CompareResponse{
+		TargetElement: requests[0].ElementTarget,
+		MaxRecipe:     data.MaxRecipe,
+		Comparison:    comparison,
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"tubes2_be_bfc/src/cmd"
+)
+
+type CraftableRequest struct {
+	Elements []string `json:"Elements"`
+	// Jumlah langkah maksimum, 0 berarti satu langkah dan -1 berarti seluruh closure
+	MaxSteps int `json:"MaxSteps"`
+}
+
+type CraftableResponse struct {
+	Owned     []string               `json:"owned"`
+	MaxSteps  int                    `json:"maxSteps"`
+	Craftable []cmd.CraftableElement `json:"craftable"`
+}
+
+// handleCraftable: POST /api/craftable
+func handleCraftable(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "POST, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, "POST")
+		return
+	}
+
+	var data CraftableRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidJSON, "", err.Error()))
+		return
+	}
+
+	if len(data.Elements) == 0 {
+		writeError(w, newAPIError(http.StatusBadRequest, ErrMissingField, "Elements", "Elements is required"))
+		return
+	}
+
+	ds := currentDataset()
+	maxSteps := data.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = 1
+	}
+	steps := maxSteps
+	switch {
+	case maxSteps == -1:
+		// Closure penuh selesai paling lama setelah semua elemen ditemukan
+		steps = len(ds.Recipes)
+	case maxSteps < -1:
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidMaxSteps, "MaxSteps",
+			"MaxSteps must be a positive integer, or -1 for the full closure"))
+		return
+	}
+
+	owned := make([]string, len(data.Elements))
+	for i, el := range data.Elements {
+		canonical, ok := ds.Names.Resolve(el)
+		if !ok {
+			writeError(w, elementNotFound(ds, fmt.Sprintf("Elements[%d]", i), el))
+			return
+		}
+		owned[i] = canonical
+	}
+
+	writeJSON(w, http.StatusOK, CraftableResponse{
+		Owned:     owned,
+		MaxSteps:  maxSteps,
+		Craftable: cmd.Craftable(ds.Recipes, ds.Tiers, ds.Index, owned, steps),
+	})
+}
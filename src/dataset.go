@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tubes2_be_bfc/src/cmd"
+	"tubes2_be_bfc/src/utils"
+)
+
+// Dataset adalah satu versi dataset beserta data turunannya. Setelah dipasang
+// isinya tidak pernah diubah, sehingga pencarian yang sedang berjalan tetap
+// memakai versi lama walaupun dataset dimuat ulang.
+type Dataset struct {
+	Recipes cmd.RecipeMap
+	Tiers   cmd.TierMap
+	Report  cmd.ValidationReport
+	Names   *cmd.NameResolver
+	// Indeks balik bahan → resep
+	Index cmd.ReverseIndex
+	// Jumlah pohon resep berbeda per elemen
+	TreeCounts map[string]*big.Int
+	// Versi dataset, dihitung dari isinya
+	Version  string
+	LoadedAt time.Time
+	// Data baru yang belum tersimpan di -data, nil jika dataset dibaca dari snapshot
+	snapshot *utils.Snapshot
+}
+
+// Informasi dataset aktif
+type DatasetInfo struct {
+	Version  string    `json:"version"`
+	LoadedAt time.Time `json:"loadedAt"`
+	Elements int       `json:"elements"`
+	Source   string    `json:"source"`
+	Issues   int       `json:"issues"`
+}
+
+type ReloadResponse struct {
+	DatasetInfo
+	PreviousVersion string `json:"previousVersion"`
+	// Changed bernilai false jika dataset baru sama persis dengan yang aktif
+	Changed bool `json:"changed"`
+}
+
+var (
+	active atomic.Pointer[Dataset]
+	// Mencegah dua proses muat ulang berjalan bersamaan
+	reloadMu sync.Mutex
+)
+
+// currentDataset mengembalikan dataset aktif. Handler memanggilnya sekali per request.
+func currentDataset() *Dataset {
+	return active.Load()
+}
+
+func (ds *Dataset) info() DatasetInfo {
+	return DatasetInfo{
+		Version:  ds.Version,
+		LoadedAt: ds.LoadedAt,
+		Elements: len(ds.Recipes),
+		Source:   *dataSource,
+		Issues:   ds.Report.IssueCount(),
+	}
+}
+
+// buildDataset memuat elemen dari sumber yang dikonfigurasi lalu membangun
+// seluruh data turunannya. Dataset yang gagal validasi pada mode strict ditolak.
+func buildDataset(ctx context.Context) (*Dataset, error) {
+	elements, source, err := loadElements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Berhasil memuat %d elemen", len(elements))
+
+	ds := &Dataset{LoadedAt: time.Now().UTC()}
+	if source != "" {
+		ds.snapshot = utils.NewSnapshot(elements, source)
+	}
+	ds.Recipes, ds.Tiers = splitElements(elements)
+
+	var aliases map[string]string
+	if *aliasPath != "" {
+		aliases, err = utils.LoadAliases(*aliasPath)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat alias: %w", err)
+		}
+	}
+	ds.Names = cmd.NewNameResolver(ds.Recipes, aliases)
+	ds.Index = cmd.BuildReverseIndex(ds.Recipes, ds.Tiers)
+	ds.Version = cmd.DatasetVersion(ds.Recipes, ds.Tiers)
+	ds.TreeCounts = cmd.CountRecipeTrees(ds.Recipes, ds.Tiers)
+
+	ds.Report = cmd.ValidateDataset(ds.Recipes, ds.Tiers)
+	if ds.Report.HasIssues() {
+		if *strictDataset {
+			return nil, fmt.Errorf("dataset tidak valid: %s", ds.Report.Summary())
+		}
+		log.Printf("Peringatan validasi dataset: %s", ds.Report.Summary())
+	}
+
+	return ds, nil
+}
+
+// reloadDataset membangun dataset baru di luar jalur request lalu menggantinya
+// secara atomik. Request baru langsung memakai dataset baru, cache hasil dikosongkan.
+// Scraping dibatasi -scrape-timeout sehingga reloadMu tidak tertahan jika wiki tidak merespons.
+func reloadDataset(ctx context.Context) (ReloadResponse, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	previous := currentDataset()
+	ds, err := buildDataset(ctx)
+	if err != nil {
+		return ReloadResponse{}, err
+	}
+
+	resp := ReloadResponse{PreviousVersion: previous.Version}
+	if ds.Version == previous.Version {
+		resp.DatasetInfo = previous.info()
+		return resp, nil
+	}
+
+	active.Store(ds)
+	storeSnapshot(ds)
+	if Cache != nil {
+		Cache.Purge()
+	}
+	log.Printf("Dataset dimuat ulang: versi %s → %s", previous.Version, ds.Version)
+
+	resp.DatasetInfo = ds.info()
+	resp.Changed = true
+	return resp, nil
+}
+
+// scheduleReload memuat ulang dataset secara berkala. Kegagalan hanya dicatat,
+// dataset lama tetap dipakai.
+func scheduleReload(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := reloadDataset(context.Background()); err != nil {
+			log.Printf("Gagal memuat ulang dataset: %v", err)
+		}
+	}
+}
+
+// handleDataset: GET /api/dataset mengembalikan informasi dataset aktif
+func handleDataset(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, currentDataset().info())
+}
+
+// authorizeAdmin memeriksa header Authorization: Bearer <token>
+func authorizeAdmin(r *http.Request) *APIError {
+	if *adminToken == "" {
+		return newAPIError(http.StatusForbidden, ErrAdminDisabled, "", "admin endpoints are disabled, start the server with -admin-token")
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(*adminToken)) != 1 {
+		return newAPIError(http.StatusUnauthorized, ErrUnauthorized, "", "missing or invalid admin token")
+	}
+	return nil
+}
+
+// handleReload: POST /api/admin/reload memuat ulang dataset dari sumber yang dikonfigurasi
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, "POST")
+		return
+	}
+	if apiErr := authorizeAdmin(r); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	resp, err := reloadDataset(r.Context())
+	if err != nil {
+		writeError(w, newAPIError(http.StatusUnprocessableEntity, ErrReloadFailed, "", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
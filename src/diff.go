@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"tubes2_be_bfc/src/cmd"
+	"tubes2_be_bfc/src/utils"
+)
+
+// splitElements memisahkan data elemen menjadi peta resep dan peta tier
+func splitElements(elements map[string]utils.ElementInfo) (cmd.RecipeMap, cmd.TierMap) {
+	recipes := make(cmd.RecipeMap, len(elements))
+	tiers := make(cmd.TierMap, len(elements))
+	for key, val := range elements {
+		recipes[key] = val.Recipes
+		tiers[key] = val.Tier
+	}
+	return recipes, tiers
+}
+
+// loadElementsFile membaca dataset dari file snapshot JSON atau halaman wiki HTML
+func loadElementsFile(path string) (map[string]utils.ElementInfo, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return utils.LoadAlchemyElementsFromFile(path)
+	default:
+		snapshot, err := utils.LoadSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		return snapshot.Elements, nil
+	}
+}
+
+// writeDiff menulis diff sebagai teks atau JSON
+func writeDiff(w io.Writer, diff cmd.DatasetDiff, format string) error {
+	if format == "text" {
+		_, err := fmt.Fprint(w, diff.Text())
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diff)
+}
+
+// runDiff membandingkan file dataset lama di path dengan dataset dari -source
+// lalu mencetak hasilnya, dipakai oleh flag -diff
+func runDiff(path, format string) error {
+	oldElements, err := loadElementsFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %w", path, err)
+	}
+
+	newElements, _, err := loadElements(context.Background())
+	if err != nil {
+		return err
+	}
+
+	oldRecipes, oldTiers := splitElements(oldElements)
+	newRecipes, newTiers := splitElements(newElements)
+	return writeDiff(os.Stdout, cmd.DiffDatasets(oldRecipes, oldTiers, newRecipes, newTiers), format)
+}
+
+// handleDiff: GET /api/admin/diff?against=snapshot|scrape&format=json|text membandingkan
+// dataset aktif dengan snapshot di disk atau hasil scraping terbaru
+func handleDiff(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+	if apiErr := authorizeAdmin(r); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "text":
+	default:
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidFormat, "format",
+			fmt.Sprintf("unknown format %q, expected json or text", format)))
+		return
+	}
+
+	var elements map[string]utils.ElementInfo
+	var err error
+	switch against := r.URL.Query().Get("against"); against {
+	case "", "snapshot":
+		elements, err = loadElementsFile(*dataPath)
+	case "scrape":
+		// Scraping berhenti saat client terputus atau -scrape-timeout tercapai
+		ctx, cancel := context.WithTimeout(r.Context(), *scrapeTimeout)
+		defer cancel()
+		elements, err = utils.ScrapeAlchemyElements(ctx)
+	default:
+		writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidQuery, "against",
+			fmt.Sprintf("unknown dataset %q, expected snapshot or scrape", against)))
+		return
+	}
+	if err != nil {
+		writeError(w, newAPIError(http.StatusBadGateway, ErrDatasetUnavailable, "against", err.Error()))
+		return
+	}
+
+	ds := currentDataset()
+	recipes, tiers := splitElements(elements)
+	diff := cmd.DiffDatasets(ds.Recipes, ds.Tiers, recipes, tiers)
+
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	writeDiff(w, diff, format)
+}
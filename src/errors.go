@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"tubes2_be_bfc/src/cmd"
+)
+
+// Kode error yang dikembalikan API
+const (
+	ErrInvalidJSON        = "invalid_json"
+	ErrMissingField       = "missing_field"
+	ErrInvalidAlgorithm   = "invalid_algorithm"
+	ErrInvalidMaxRecipe   = "invalid_max_recipe"
+	ErrInvalidCostMode    = "invalid_cost_mode"
+	ErrInvalidDepthLimit  = "invalid_depth_limit"
+	ErrInvalidMaxSteps    = "invalid_max_steps"
+	ErrInvalidConstraint  = "invalid_constraint"
+	ErrInvalidFormat      = "invalid_format"
+	ErrStreamUnsupported  = "stream_unsupported"
+	ErrElementNotFound    = "element_not_found"
+	ErrInvalidQuery       = "invalid_query"
+	ErrMethodNotAllowed   = "method_not_allowed"
+	ErrAdminDisabled      = "admin_disabled"
+	ErrUnauthorized       = "unauthorized"
+	ErrReloadFailed       = "reload_failed"
+	ErrDatasetUnavailable = "dataset_unavailable"
+)
+
+// APIError adalah isi envelope error JSON
+type APIError struct {
+	Status      int      `json:"status"`
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Field       string   `json:"field,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func newAPIError(status int, code, field, message string) *APIError {
+	return &APIError{Status: status, Code: code, Field: field, Message: message}
+}
+
+func writeError(w http.ResponseWriter, err *APIError) {
+	writeJSON(w, err.Status, ErrorResponse{Error: err})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	writeError(w, newAPIError(http.StatusMethodNotAllowed, ErrMethodNotAllowed, "", "Only "+allowed+" allowed"))
+}
+
+// elementNotFound membuat error 404 beserta saran nama elemen yang mirip
+func elementNotFound(ds *Dataset, field, name string) *APIError {
+	apiErr := newAPIError(http.StatusNotFound, ErrElementNotFound, field, fmt.Sprintf("element %q not found", name))
+	apiErr.Suggestions = cmd.SuggestElements(ds.Recipes, name, 5)
+	return apiErr
+}
@@ -1,17 +1,22 @@
 package main
 
 import (
+    "context"
     "encoding/json"
+    "flag"
+    "fmt"
     "net/http"
+    "slices"
+    "strings"
+    "time"
     "tubes2_be_bfc/src/cmd"
 	"tubes2_be_bfc/src/utils"
 	"log"
-    // "os"
 )
 
 var (
-    Recipes cmd.RecipeMap
-    Tiers   cmd.TierMap
+    // Cache hasil pencarian bersama, dibuat di main setelah flag dibaca
+    Cache *cmd.ResultCache
 )
 
 type RequestData struct {
@@ -19,73 +24,346 @@ type RequestData struct {
     AlgorithmType string `json:"AlgorithmType"`
     Multiple      bool   `json:"Multiple"`
     MaxRecipe     int    `json:"MaxRecipe"`
+    CostMode      string `json:"CostMode"`
+    // Batas tinggi pohon untuk dfs dan iddfs, 0 berarti tanpa batas
+    DepthLimit    int    `json:"DepthLimit"`
+    // nil berarti memakai nilai bawaan (deterministic aktif)
+    Deterministic *bool  `json:"Deterministic"`
+    // Elemen yang sudah dimiliki pemain, diperlakukan sebagai daun pohon resep
+    Inventory     []string `json:"Inventory"`
+    // Elemen yang tidak boleh dipakai dan elemen yang wajib muncul di setiap pohon
+    Exclude       []string `json:"Exclude"`
+    Require       []string `json:"Require"`
+    // Format hasil: tree (bawaan), plan, atau dag
+    Format        string   `json:"Format"`
 }
-func init() {
 
-  
-    scrapData, err := utils.ScrapeAlchemyElements()
+// searchOptions membangun opsi pencarian dari request
+func (data RequestData) searchOptions() *cmd.SearchOptions {
+    opts := cmd.DefaultSearchOptions()
+    opts.MaxNodes = *maxNodes
+    if data.Deterministic != nil {
+        opts.Deterministic = *data.Deterministic
+    }
+    if len(data.Inventory) > 0 {
+        opts.Inventory = make(map[string]bool, len(data.Inventory))
+        for _, el := range data.Inventory {
+            opts.Inventory[el] = true
+        }
+    }
+    if len(data.Exclude) > 0 {
+        opts.Exclude = make(map[string]bool, len(data.Exclude))
+        for _, el := range data.Exclude {
+            opts.Exclude[el] = true
+        }
+    }
+    opts.Require = data.Require
+    return opts
+}
+
+var (
+    dataPath       = flag.String("data", "src/data/alchemy_recipes.json", "path file snapshot dataset")
+    dataSource     = flag.String("source", "snapshot", "sumber dataset: snapshot, scrape, atau html")
+    htmlPath       = flag.String("html", "", "path halaman wiki Elements_(Little_Alchemy_2) yang disimpan lokal (untuk -source html)")
+    scrapeFallback = flag.Bool("scrape-fallback", false, "lakukan scraping jika snapshot gagal dibaca")
+    saveSnapshot   = flag.Bool("save", true, "simpan hasil scraping ke file snapshot")
+    strictDataset  = flag.Bool("strict", false, "hentikan server jika dataset tidak lolos validasi")
+    searchTimeout  = flag.Duration("search-timeout", 10*time.Second, "batas waktu setiap pencarian")
+    maxNodes       = flag.Int64("max-nodes", 500000, "batas jumlah node yang dibangun setiap pencarian (0 = tanpa batas)")
+    aliasPath      = flag.String("aliases", "", "path file JSON tabel alias nama elemen (alias -> nama kanonik)")
+    cacheEntries   = flag.Int("cache-entries", 256, "jumlah maksimum hasil pencarian di cache (0 = tanpa batas)")
+    cacheMB        = flag.Int64("cache-mb", 64, "perkiraan memori maksimum cache hasil dalam MB (0 = tanpa batas)")
+    disableCache   = flag.Bool("no-cache", false, "nonaktifkan cache hasil pencarian")
+    reloadInterval = flag.Duration("reload-interval", 0, "muat ulang dataset secara berkala (0 = nonaktif)")
+    adminToken     = flag.String("admin-token", "", "token untuk endpoint admin, kosong berarti endpoint admin nonaktif")
+    diffPath       = flag.String("diff", "", "bandingkan file dataset ini (snapshot JSON atau halaman HTML) dengan dataset dari -source, cetak hasilnya lalu keluar")
+    diffFormat     = flag.String("diff-format", "text", "format keluaran -diff: text atau json")
+    scrapeTimeout  = flag.Duration("scrape-timeout", time.Minute, "batas waktu mengambil halaman wiki saat scraping")
+)
+
+// loadElements membaca dataset sesuai mode startup yang dipilih. source berisi asal
+// data jika data baru dibaca dari wiki atau halaman HTML, kosong jika dari snapshot.
+// Scraping dibatalkan saat ctx selesai atau setelah -scrape-timeout.
+func loadElements(ctx context.Context) (elements map[string]utils.ElementInfo, source string, err error) {
+    switch *dataSource {
+    case "scrape":
+    case "html":
+        if *htmlPath == "" {
+            return nil, "", fmt.Errorf("-html wajib diisi untuk -source html")
+        }
+        elements, err := utils.LoadAlchemyElementsFromFile(*htmlPath)
+        if err != nil {
+            return nil, "", err
+        }
+        log.Printf("Memuat dataset dari halaman lokal %s", *htmlPath)
+        return elements, "file://" + *htmlPath, nil
+    case "snapshot":
+        snapshot, err := utils.LoadSnapshot(*dataPath)
+        if err == nil {
+            log.Printf("Memuat snapshot %s (scraped %s dari %s)",
+                *dataPath, snapshot.ScrapedAt.Format(time.RFC3339), snapshot.SourceURL)
+            return snapshot.Elements, "", nil
+        }
+        if !*scrapeFallback {
+            return nil, "", err
+        }
+        log.Printf("Gagal memuat snapshot, melakukan scraping: %v", err)
+    default:
+        return nil, "", fmt.Errorf("-source tidak dikenal %q, gunakan snapshot, scrape, atau html", *dataSource)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, *scrapeTimeout)
+    defer cancel()
+    scrapData, err := utils.ScrapeAlchemyElements(ctx)
+    if err != nil {
+        return nil, "", fmt.Errorf("gagal melakukan scraping: %w", err)
+    }
+
+    return scrapData, utils.WikiURL, nil
+}
+
+// storeSnapshot menyimpan data baru dataset ke -data. Dipanggil setelah dataset
+// lolos buildDataset dan dipasang, sehingga data yang ditolak tidak menimpa snapshot.
+func storeSnapshot(ds *Dataset) {
+    if ds.snapshot == nil || !*saveSnapshot {
+        return
+    }
+    if err := utils.SaveSnapshot(ds.snapshot, *dataPath); err != nil {
+        log.Printf("Gagal menyimpan snapshot: %v", err)
+    } else {
+        log.Printf("Snapshot disimpan ke %s", *dataPath)
+    }
+}
+
+func loadDataset() {
+    ds, err := buildDataset(context.Background())
     if err != nil {
-        log.Fatalf("Gagal melakukan scraping: %v", err)
-    }
-    
-    log.Printf("Berhasil scraping %d elemen", len(scrapData))
-    
-    Recipes = make(cmd.RecipeMap)
-    Tiers = make(cmd.TierMap)
- 
-    for key, val := range scrapData {
-        Recipes[key] = val.Recipes
-        Tiers[key] = val.Tier
-    }
-    
-    log.Println("Persiapan data selesai, siap menerima permintaan.")
+        log.Fatalf("Gagal memuat dataset: %v", err)
+    }
+    active.Store(ds)
+    storeSnapshot(ds)
+    log.Printf("Persiapan data selesai (versi %s), siap menerima permintaan.", ds.Version)
 }
+
+// validateRequest memeriksa isi request pencarian sebelum dijalankan
+// dan mengganti ElementTarget dengan nama kanoniknya
+func validateRequest(ds *Dataset, data *RequestData) *APIError {
+    if data.AlgorithmType == "" {
+        return newAPIError(http.StatusBadRequest, ErrMissingField, "AlgorithmType", "AlgorithmType is required")
+    }
+    searcher, ok := cmd.Lookup(data.AlgorithmType)
+    if !ok {
+        names := make([]string, 0)
+        for _, info := range cmd.Algorithms() {
+            names = append(names, info.Name)
+        }
+        return newAPIError(http.StatusBadRequest, ErrInvalidAlgorithm, "AlgorithmType",
+            fmt.Sprintf("unknown algorithm %q, expected one of %s", data.AlgorithmType, strings.Join(names, ", ")))
+    }
+    info := searcher.Info()
+
+    if param, ok := info.Param(cmd.ParamCostMode); ok && data.CostMode != "" && !slices.Contains(param.Values, data.CostMode) {
+        return newAPIError(http.StatusBadRequest, ErrInvalidCostMode, "CostMode",
+            fmt.Sprintf("unknown cost mode %q, expected %s", data.CostMode, strings.Join(param.Values, " or ")))
+    }
+
+    if data.DepthLimit < 0 {
+        return newAPIError(http.StatusBadRequest, ErrInvalidDepthLimit, "DepthLimit", "DepthLimit must be zero or a positive integer")
+    }
+
+    // Algoritma yang tidak memakai MaxRecipe mengabaikannya
+    if param, ok := info.Param(cmd.ParamMaxRecipe); ok {
+        if param.Required && data.MaxRecipe <= 0 {
+            return newAPIError(http.StatusBadRequest, ErrInvalidMaxRecipe, "MaxRecipe", "MaxRecipe must be a positive integer")
+        }
+        if data.MaxRecipe > cmd.MaxRecipeLimit {
+            return newAPIError(http.StatusBadRequest, ErrInvalidMaxRecipe, "MaxRecipe",
+                fmt.Sprintf("MaxRecipe must be at most %d", cmd.MaxRecipeLimit))
+        }
+    }
+
+    switch data.Format {
+    case "", "tree", "plan", "dag":
+    default:
+        return newAPIError(http.StatusBadRequest, ErrInvalidFormat, "Format",
+            fmt.Sprintf("unknown format %q, expected tree, plan or dag", data.Format))
+    }
+
+    if strings.TrimSpace(data.ElementTarget) == "" {
+        return newAPIError(http.StatusBadRequest, ErrMissingField, "ElementTarget", "ElementTarget is required")
+    }
+    canonical, ok := ds.Names.Resolve(data.ElementTarget)
+    if !ok {
+        return elementNotFound(ds, "ElementTarget", data.ElementTarget)
+    }
+    data.ElementTarget = canonical
+
+    for _, list := range []struct {
+        field string
+        names []string
+    }{{"Inventory", data.Inventory}, {"Exclude", data.Exclude}, {"Require", data.Require}} {
+        for i, el := range list.names {
+            canonical, ok := ds.Names.Resolve(el)
+            if !ok {
+                return elementNotFound(ds, fmt.Sprintf("%s[%d]", list.field, i), el)
+            }
+            list.names[i] = canonical
+        }
+    }
+
+    if _, ok := info.Param(cmd.ParamRequire); len(data.Require) > 0 && !ok {
+        return newAPIError(http.StatusBadRequest, ErrInvalidConstraint, "Require",
+            fmt.Sprintf("Require is not supported by %s", data.AlgorithmType))
+    }
+    if len(data.Require) > cmd.MaxRequiredElements {
+        return newAPIError(http.StatusBadRequest, ErrInvalidConstraint, "Require",
+            fmt.Sprintf("at most %d required elements are supported", cmd.MaxRequiredElements))
+    }
+    for i, el := range data.Exclude {
+        if el == data.ElementTarget {
+            return newAPIError(http.StatusBadRequest, ErrInvalidConstraint, fmt.Sprintf("Exclude[%d]", i),
+                "ElementTarget cannot be excluded")
+        }
+        for _, required := range data.Require {
+            if el == required {
+                return newAPIError(http.StatusBadRequest, ErrInvalidConstraint, fmt.Sprintf("Exclude[%d]", i),
+                    fmt.Sprintf("element %q is both required and excluded", el))
+            }
+        }
+    }
+
+    return nil
+}
+
+// runSearch menjalankan algoritma yang diminta pada dataset ds lalu mengubah format hasilnya
+func runSearch(ctx context.Context, ds *Dataset, data RequestData, opts *cmd.SearchOptions) cmd.Result {
+    // AlgorithmType sudah diperiksa oleh validateRequest
+    searcher, _ := cmd.Lookup(data.AlgorithmType)
+    results := searcher.Search(ctx, ds.Recipes, ds.Tiers, cmd.SearchRequest{
+        Target:     data.ElementTarget,
+        MaxRecipe:  data.MaxRecipe,
+        CostMode:   data.CostMode,
+        DepthLimit: data.DepthLimit,
+        Options:    opts,
+        Index:      ds.Index,
+    })
+    results.DatasetVersion = ds.Version
+
+    applyFormat(&results, data.Format)
+
+    return results
+}
+
+// cachedSearch seperti runSearch namun memakai cache hasil bersama. Cache menyimpan
+// hasil dalam format tree, format diterapkan pada salinan Result setiap kali diambil.
+func cachedSearch(ctx context.Context, ds *Dataset, data RequestData, opts *cmd.SearchOptions) cmd.Result {
+    if Cache == nil {
+        return runSearch(ctx, ds, data, opts)
+    }
+
+    key := cmd.CacheKey{
+        Version:       ds.Version,
+        Algorithm:     data.AlgorithmType,
+        Target:        data.ElementTarget,
+        MaxRecipe:     data.MaxRecipe,
+        CostMode:      data.CostMode,
+        DepthLimit:    data.DepthLimit,
+        Deterministic: opts.Deterministic,
+        Inventory:     data.Inventory,
+        Exclude:       data.Exclude,
+        Require:       data.Require,
+    }
+    if results, ok := Cache.Get(key); ok {
+        results.Cached = true
+        applyFormat(&results, data.Format)
+        return results
+    }
+
+    format := data.Format
+    data.Format = ""
+    results := runSearch(ctx, ds, data, opts)
+    // Hasil parsial bergantung pada waktu dan beban server sehingga tidak disimpan,
+    // begitu pula hasil dari dataset yang sudah diganti selama pencarian berjalan
+    if !results.Truncated && ds == currentDataset() {
+        Cache.Add(key, results)
+    }
+    applyFormat(&results, format)
+    return results
+}
+
+// applyFormat mengubah pohon pada Result sesuai format yang diminta
+func applyFormat(results *cmd.Result, format string) {
+    switch format {
+    case "plan":
+        cmd.UsePlanFormat(results)
+    case "dag":
+        cmd.UseDagFormat(results)
+    }
+}
+
 func handleData(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    w.Header().Set("Access-Control-Allow-Origin", "*")
-    w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-    w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+    setCORSHeaders(w, "POST, OPTIONS")
 
     if r.Method == http.MethodOptions {
         return
     }
 
     if r.Method != http.MethodPost {
-        http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+        writeMethodNotAllowed(w, "POST")
         return
     }
-    if r.Method == http.MethodPost {
-        var data RequestData
-        err := json.NewDecoder(r.Body).Decode(&data)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusBadRequest)
-            return
-        }
 
-        // var response ResponseData
-        var results cmd.Result
-
-        if data.AlgorithmType == "bfs" {
-            // BFS
-            results = cmd.MainBfs(Recipes, Tiers, data.ElementTarget, data.MaxRecipe)
-        } else if data.AlgorithmType == "dfs" {
-            // DFS
-            results = cmd.MainDfs(Recipes, Tiers, data.ElementTarget, data.MaxRecipe)
-        } else if data.AlgorithmType == "bidirectional" {
-            // BIDIRECTIONAL
-            // results = cmd.BidirectionalBfs(recipes, tiers, data.ElementTarget, data.MaxRecipe)
-            results = cmd.MainBidirectionalBfs(Recipes, Tiers, data.ElementTarget, data.MaxRecipe)
-        }
-
-        w.WriteHeader(http.StatusOK)
-        json.NewEncoder(w).Encode(results)
+    var data RequestData
+    err := json.NewDecoder(r.Body).Decode(&data)
+    if err != nil {
+        writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidJSON, "", err.Error()))
+        return
+    }
 
-    } else {
-        http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+    // Seluruh request memakai snapshot dataset yang sama walaupun dataset dimuat ulang
+    ds := currentDataset()
+    if apiErr := validateRequest(ds, &data); apiErr != nil {
+        writeError(w, apiErr)
+        return
     }
+
+    // Pencarian berhenti saat client terputus atau batas waktu server tercapai
+    ctx, cancel := context.WithTimeout(r.Context(), *searchTimeout)
+    defer cancel()
+
+    results := cachedSearch(ctx, ds, data, data.searchOptions())
+
+    writeJSON(w, http.StatusOK, results)
 }
 
 func main() {
+    flag.Parse()
+    if *diffPath != "" {
+        if err := runDiff(*diffPath, *diffFormat); err != nil {
+            log.Fatalf("Gagal membandingkan dataset: %v", err)
+        }
+        return
+    }
+    if !*disableCache {
+        Cache = cmd.NewResultCache(*cacheEntries, *cacheMB<<20)
+    }
+    loadDataset()
+    if *reloadInterval > 0 {
+        go scheduleReload(*reloadInterval)
+    }
+
     http.HandleFunc("/api/data", handleData)
+    http.HandleFunc("/api/validate", handleValidate)
+    http.HandleFunc("/api/elements", handleElements)
+    http.HandleFunc("/api/elements/{name}", handleElement)
+    http.HandleFunc("/api/elements/{name}/used-in", handleElementUsage)
+    http.HandleFunc("/api/craftable", handleCraftable)
+    http.HandleFunc("/api/stream", handleStream)
+    http.HandleFunc("/api/compare", handleCompare)
+    http.HandleFunc("/api/algorithms", handleAlgorithms)
+    http.HandleFunc("/api/cache", handleCache)
+    http.HandleFunc("/api/dataset", handleDataset)
+    http.HandleFunc("/api/admin/reload", handleReload)
+    http.HandleFunc("/api/admin/diff", handleDiff)
     http.ListenAndServe(":8080", nil)
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"tubes2_be_bfc/src/cmd"
+)
+
+// Ukuran buffer event; worker pencarian menunggu jika client lambat membaca
+const streamBufferSize = 256
+
+// requestFromQuery membaca RequestData dari query string, karena EventSource hanya mendukung GET.
+// Daftar elemen dipisahkan dengan koma.
+func requestFromQuery(r *http.Request) (RequestData, *APIError) {
+	q := r.URL.Query()
+	data := RequestData{
+		ElementTarget: q.Get("ElementTarget"),
+		AlgorithmType: q.Get("AlgorithmType"),
+		CostMode:      q.Get("CostMode"),
+		Format:        q.Get("Format"),
+	}
+
+	if raw := q.Get("MaxRecipe"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return data, newAPIError(http.StatusBadRequest, ErrInvalidMaxRecipe, "MaxRecipe", "MaxRecipe must be a positive integer")
+		}
+		data.MaxRecipe = n
+	}
+	if raw := q.Get("DepthLimit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return data, newAPIError(http.StatusBadRequest, ErrInvalidDepthLimit, "DepthLimit", "DepthLimit must be zero or a positive integer")
+		}
+		data.DepthLimit = n
+	}
+	if raw := q.Get("Deterministic"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return data, newAPIError(http.StatusBadRequest, ErrInvalidQuery, "Deterministic", "Deterministic must be true or false")
+		}
+		data.Deterministic = &b
+	}
+
+	splitList := func(key string) []string {
+		var list []string
+		for _, item := range strings.Split(q.Get(key), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	data.Inventory = splitList("Inventory")
+	data.Exclude = splitList("Exclude")
+	data.Require = splitList("Require")
+
+	return data, nil
+}
+
+// writeEvent menulis satu event SSE
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, v any) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	flusher.Flush()
+}
+
+// handleStream: GET /api/stream?ElementTarget=&AlgorithmType=&MaxRecipe= (atau POST dengan body JSON)
+// mengirim event progres pencarian sebagai Server-Sent Events, diakhiri event result
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, "GET, POST, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	var data RequestData
+	switch r.Method {
+	case http.MethodGet:
+		var apiErr *APIError
+		if data, apiErr = requestFromQuery(r); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			writeError(w, newAPIError(http.StatusBadRequest, ErrInvalidJSON, "", err.Error()))
+			return
+		}
+	default:
+		writeMethodNotAllowed(w, "GET or POST")
+		return
+	}
+
+	ds := currentDataset()
+	if apiErr := validateRequest(ds, &data); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, newAPIError(http.StatusInternalServerError, ErrStreamUnsupported, "", "streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithTimeout(r.Context(), *searchTimeout)
+	defer cancel()
+
+	events := make(chan cmd.SearchEvent, streamBufferSize)
+	done := make(chan cmd.Result, 1)
+
+	opts := data.searchOptions()
+	opts.OnEvent = func(ev cmd.SearchEvent) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		done <- runSearch(ctx, ds, data, opts)
+	}()
+
+	for {
+		select {
+		case ev := <-events:
+			writeEvent(w, flusher, ev.Type, ev)
+		case result := <-done:
+			// Kirim sisa event yang masih di buffer sebelum hasil akhir
+		drain:
+			for {
+				select {
+				case ev := <-events:
+					writeEvent(w, flusher, ev.Type, ev)
+				default:
+					break drain
+				}
+			}
+			writeEvent(w, flusher, "result", result)
+			return
+		}
+	}
+}
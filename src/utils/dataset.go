@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotSchemaVersion adalah versi format file snapshot yang didukung
+const SnapshotSchemaVersion = 1
+
+// Snapshot adalah dataset hasil scraping yang disimpan ke file JSON
+type Snapshot struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	ScrapedAt     time.Time              `json:"scrapedAt"`
+	SourceURL     string                 `json:"sourceUrl"`
+	Elements      map[string]ElementInfo `json:"elements"`
+}
+
+// NewSnapshot membungkus data elemen menjadi snapshot dengan versi skema terbaru
+func NewSnapshot(elements map[string]ElementInfo, sourceURL string) *Snapshot {
+	return &Snapshot{
+		SchemaVersion: SnapshotSchemaVersion,
+		ScrapedAt:     time.Now().UTC(),
+		SourceURL:     sourceURL,
+		Elements:      elements,
+	}
+}
+
+// SaveSnapshot menyimpan snapshot ke file JSON
+func SaveSnapshot(snapshot *Snapshot, path string) error {
+	jsonData, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error creating JSON: %w", err)
+	}
+
+	// Buat direktori jika belum ada
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating directory: %w", err)
+		}
+	}
+
+	// Tulis ke file sementara lalu rename agar file lama tidak rusak jika gagal
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, jsonData, 0644); err != nil {
+		return fmt.Errorf("error writing JSON file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot membaca snapshot dari file JSON
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading JSON file: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	if snapshot.SchemaVersion != SnapshotSchemaVersion {
+		return nil, fmt.Errorf("unsupported snapshot schema version %d (expected %d)",
+			snapshot.SchemaVersion, SnapshotSchemaVersion)
+	}
+	if len(snapshot.Elements) == 0 {
+		return nil, fmt.Errorf("snapshot %s contains no elements", path)
+	}
+
+	return &snapshot, nil
+}
+
+// LoadAliases membaca tabel alias nama elemen (alias -> nama kanonik) dari file JSON
+func LoadAliases(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading alias file: %w", err)
+	}
+
+	var aliases map[string]string
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("error parsing alias file: %w", err)
+	}
+
+	return aliases, nil
+}
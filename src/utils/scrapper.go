@@ -2,11 +2,11 @@ package utils
 
 
 import (
-	// "encoding/json"
+	"context"
 	"fmt"
-	// "io/ioutil"
+	"io"
 	"net/http"
-	// "os"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,10 +15,17 @@ import (
 )
 
 // ElementInfo adalah struktur untuk menyimpan informasi tentang elemen
-// type ElementInfo struct {
-// 	Tier    int        `json:"tier"`
-// 	Recipes [][]string `json:"recipes"`
-// }
+type ElementInfo struct {
+	Tier    int        `json:"tier"`
+	Recipes [][]string `json:"recipes"`
+}
+
+// WikiURL adalah halaman wiki sumber data elemen
+const WikiURL = "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)"
+
+// wikiClient dipakai untuk semua request ke wiki. Timeout menjadi batas terakhir
+// jika context pemanggil tidak memiliki batas waktu.
+var wikiClient = &http.Client{Timeout: 2 * time.Minute}
 
 // CleanText menghilangkan whitespace berlebih dari string
 func CleanText(text string) string {
@@ -59,17 +66,11 @@ func ParseRecipes(recipeCell *goquery.Selection) [][]string {
 	return recipes
 }
 
-// ScrapeAlchemyElements melakukan scraping pada wiki Little Alchemy 2
-func ScrapeAlchemyElements() (map[string]struct {
-	Tier    int        `json:"tier"`
-	Recipes [][]string `json:"recipes"`
-}, error) {
-	startTime := time.Now()
-	
-	// Siapkan HTTP client dengan User-Agent untuk menghindari pemblokiran
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", 
-		"https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)", nil)
+// FetchWikiPage mengambil halaman HTML wiki dari url yang diberikan.
+// Request dibatalkan saat ctx selesai.
+func FetchWikiPage(ctx context.Context, url string) (io.ReadCloser, error) {
+	// Siapkan request dengan User-Agent untuk menghindari pemblokiran
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
@@ -78,35 +79,64 @@ func ScrapeAlchemyElements() (map[string]struct {
 		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
 	
 	// Kirim request
-	resp, err := client.Do(req)
+	resp, err := wikiClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching URL: %w", err)
 	}
-	defer resp.Body.Close()
 	
 	if resp.StatusCode != 200 {
+		resp.Body.Close()
 		return nil, fmt.Errorf("status code error: %d %s", resp.StatusCode, resp.Status)
 	}
 	
+	return resp.Body, nil
+}
+
+// ScrapeAlchemyElements melakukan scraping pada wiki Little Alchemy 2
+func ScrapeAlchemyElements(ctx context.Context) (map[string]ElementInfo, error) {
+	startTime := time.Now()
+	
+	body, err := FetchWikiPage(ctx, WikiURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	
+	elements, err := ParseAlchemyElements(body)
+	if err != nil {
+		return nil, err
+	}
+	
+	fmt.Printf("Scraping completed in %s\n", time.Since(startTime))
+	return elements, nil
+}
+
+// LoadAlchemyElementsFromFile mem-parsing halaman wiki yang disimpan secara lokal
+func LoadAlchemyElementsFromFile(path string) (map[string]ElementInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening HTML file: %w", err)
+	}
+	defer file.Close()
+	
+	return ParseAlchemyElements(file)
+}
+
+// ParseAlchemyElements mengekstrak data elemen dari halaman HTML Elements_(Little_Alchemy_2)
+func ParseAlchemyElements(r io.Reader) (map[string]ElementInfo, error) {
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing HTML: %w", err)
 	}
 	
-	// Inisialisasi map untuk menyimpan data elemen dengan struct anonim
-	elements := make(map[string]struct {
-		Tier    int        `json:"tier"`
-		Recipes [][]string `json:"recipes"`
-	})
+	// Inisialisasi map untuk menyimpan data elemen
+	elements := make(map[string]ElementInfo)
 	
 	// Tambahkan elemen dasar secara manual
 	baseElements := []string{"air", "earth", "fire", "water"}
 	for _, base := range baseElements {
-		elements[base] = struct {
-			Tier    int        `json:"tier"`
-			Recipes [][]string `json:"recipes"`
-		}{
+		elements[base] = ElementInfo{
 			Tier:    0,
 			Recipes: [][]string{},
 		}
@@ -183,10 +213,7 @@ func ScrapeAlchemyElements() (map[string]struct {
 			recipes := ParseRecipes(recipeCell)
 			
 			if len(recipes) > 0 {
-				elements[elementName] = struct {
-					Tier    int        `json:"tier"`
-					Recipes [][]string `json:"recipes"`
-				}{
+				elements[elementName] = ElementInfo{
 					Tier:    tier,
 					Recipes: recipes,
 				}
@@ -195,116 +222,12 @@ func ScrapeAlchemyElements() (map[string]struct {
 	})
 	
 	// Tambahkan debug info
-	elapsedTime := time.Since(startTime)
-	fmt.Printf("Scraping completed in %s\n", elapsedTime)
 	fmt.Printf("Found %d elements (including %d base elements)\n", 
 		len(elements), len(baseElements))
 	
+	if len(elements) == len(baseElements) {
+		return nil, fmt.Errorf("no tier tables found in page")
+	}
+	
 	return elements, nil
 }
-
-// // SaveElementsToJSON menyimpan data elemen ke file JSON
-// func SaveElementsToJSON(elements map[string]ElementInfo, filepath string) error {
-// 	// Buat JSON
-// 	jsonData, err := json.MarshalIndent(elements, "", "  ")
-// 	if err != nil {
-// 		return fmt.Errorf("error creating JSON: %w", err)
-// 	}
-	
-// 	// Buat direktori jika belum ada
-// 	dir := strings.TrimSuffix(filepath, "/"+filepath)
-// 	if dir != "" && dir != filepath {
-// 		err = os.MkdirAll(dir, 0755)
-// 		if err != nil {
-// 			return fmt.Errorf("error creating directory: %w", err)
-// 		}
-// 	}
-	
-// 	// Tulis ke file
-// 	err = ioutil.WriteFile(filepath, jsonData, 0644)
-// 	if err != nil {
-// 		return fmt.Errorf("error writing JSON file: %w", err)
-// 	}
-	
-// 	fmt.Printf("Successfully saved data to %s\n", filepath)
-// 	return nil
-// }
-
-// // LoadElementsFromJSON membaca data elemen dari file JSON
-// func LoadElementsFromJSON(filepath string) (map[string]ElementInfo, error) {
-// 	// Baca file
-// 	data, err := ioutil.ReadFile(filepath)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("error reading JSON file: %w", err)
-// 	}
-	
-// 	// Parse JSON
-// 	var elements map[string]ElementInfo
-// 	err = json.Unmarshal(data, &elements)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("error parsing JSON: %w", err)
-// 	}
-	
-// 	return elements, nil
-// }
-
-// // Contoh Fungsi main untuk testing
-// func main() {
-// 	// Test scraping
-// 	fmt.Println("Starting Little Alchemy 2 scraper...")
-	
-// 	// Scrape elemen dari wiki
-// 	elements, err := ScrapeAlchemyElements()
-// 	if err != nil {
-// 		fmt.Printf("Error scraping elements: %v\n", err)
-// 		os.Exit(1)
-// 	}
-	
-// 	// Buat direktori untuk output jika belum ada
-// 	err = os.MkdirAll("src/data", 0755)
-// 	if err != nil {
-// 		fmt.Printf("Error creating directory: %v\n", err)
-// 		os.Exit(1)
-// 	}
-	
-// 	// Simpan ke file JSON
-// 	outputPath := "src/data/alchemy_recipes.json"
-// 	err = SaveElementsToJSON(elements, outputPath)
-// 	if err != nil {
-// 		fmt.Printf("Error saving JSON: %v\n", err)
-// 		os.Exit(1)
-// 	}
-	
-// 	// Tes membaca dari file JSON
-// 	fmt.Println("\nTesting JSON loading...")
-// 	loadedElements, err := LoadElementsFromJSON(outputPath)
-// 	if err != nil {
-// 		fmt.Printf("Error loading JSON: %v\n", err)
-// 		os.Exit(1)
-// 	}
-	
-// 	// Verifikasi data
-// 	fmt.Printf("Successfully loaded %d elements from JSON\n", len(loadedElements))
-	
-// 	// Tampilkan beberapa elemen
-// 	fmt.Println("\nSample elements:")
-// 	sampleElements := []string{"brick", "volcano", "human", "time"}
-// 	for _, name := range sampleElements {
-// 		if info, exists := loadedElements[name]; exists {
-// 			fmt.Printf("- %s (Tier %d) has %d recipe(s)\n", 
-// 				name, info.Tier, len(info.Recipes))
-// 			for i, recipe := range info.Recipes {
-// 				if i < 3 { // Tunjukkan max 3 resep
-// 					fmt.Printf("  * %s + %s\n", recipe[0], recipe[1])
-// 				}
-// 			}
-// 			if len(info.Recipes) > 3 {
-// 				fmt.Printf("  * (and %d more recipes)\n", len(info.Recipes)-3)
-// 			}
-// 		} else {
-// 			fmt.Printf("- %s: Not found\n", name)
-// 		}
-// 	}
-	
-// 	fmt.Println("\nScraper test completed successfully!")
-// }
\ No newline at end of file